@@ -27,7 +27,7 @@ func ChangeMapCommandRegister(l logrus.FieldLogger, db *gorm.DB) (string, handle
 
 func handleChangeMap(db *gorm.DB) func(l logrus.FieldLogger, ctx context.Context, command commandEvent[changeMapBody]) {
 	return func(l logrus.FieldLogger, ctx context.Context, command commandEvent[changeMapBody]) {
-		err := ChangeMap(l, db, ctx)(command.CharacterId, command.WorldId, command.Body.ChannelId, command.Body.MapId, command.Body.PortalId)
+		err := ChangeMap(l, db.WithContext(ctx), ctx)(command.CharacterId, command.WorldId, command.Body.ChannelId, command.Body.MapId, command.Body.PortalId)
 		if err != nil {
 			l.WithError(err).Errorf("Unable to change character [%d] map.", command.CharacterId)
 		}
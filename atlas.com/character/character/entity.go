@@ -6,7 +6,12 @@ import (
 )
 
 func Migration(db *gorm.DB) error {
-	return db.AutoMigrate(&entity{})
+	err := db.AutoMigrate(&entity{})
+	if err != nil {
+		return err
+	}
+	// Character names are unique per tenant regardless of case. Expression indexes cannot be declared via struct tags.
+	return db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_characters_tenant_name ON characters (tenant_id, LOWER(name))").Error
 }
 
 type entity struct {
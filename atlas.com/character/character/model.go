@@ -198,6 +198,10 @@ func (m Model) GetEquipment() equipment.Model {
 	return m.equipment
 }
 
+func (m Model) GetInventory() inventory.Model {
+	return m.inventory
+}
+
 type modelBuilder struct {
 	id                 uint32
 	accountId          uint32
@@ -0,0 +1,109 @@
+package name
+
+import (
+	"errors"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Measure describes how the length of a name is counted.
+type Measure string
+
+const (
+	// MeasureCharacters counts each character once.
+	MeasureCharacters Measure = "characters"
+	// MeasureEncodedBytes counts names as the client encodes them in double-byte code pages (CP949, Shift_JIS), where
+	// ASCII characters occupy one byte and all others two.
+	MeasureEncodedBytes Measure = "bytes"
+)
+
+var ErrInvalidMeasure = errors.New("invalid name length measure")
+
+// Policy describes which names a tenant permits. The pattern must match the entire name.
+type Policy struct {
+	pattern   *regexp.Regexp
+	minLength int
+	maxLength int
+	measure   Measure
+}
+
+func NewPolicy(pattern string, minLength int, maxLength int, measure Measure) (Policy, error) {
+	if measure != MeasureCharacters && measure != MeasureEncodedBytes {
+		return Policy{}, ErrInvalidMeasure
+	}
+	r, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return Policy{}, err
+	}
+	return Policy{
+		pattern:   r,
+		minLength: minLength,
+		maxLength: maxLength,
+		measure:   measure,
+	}, nil
+}
+
+func mustPolicy(pattern string, minLength int, maxLength int, measure Measure) Policy {
+	p, err := NewPolicy(pattern, minLength, maxLength, measure)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func (p Policy) MinLength() int {
+	return p.minLength
+}
+
+func (p Policy) MaxLength() int {
+	return p.maxLength
+}
+
+func (p Policy) Measure() Measure {
+	return p.measure
+}
+
+// Length returns the length of the name as measured by the policy.
+func (p Policy) Length(name string) int {
+	if p.measure == MeasureEncodedBytes {
+		length := 0
+		for _, r := range name {
+			if r < utf8.RuneSelf {
+				length += 1
+			} else {
+				length += 2
+			}
+		}
+		return length
+	}
+	return utf8.RuneCountInString(name)
+}
+
+// Valid reports whether the name is permitted by the policy.
+func (p Policy) Valid(name string) bool {
+	if !utf8.ValidString(name) {
+		return false
+	}
+	length := p.Length(name)
+	if length < p.minLength || length > p.maxLength {
+		return false
+	}
+	return p.pattern.MatchString(name)
+}
+
+var defaultPolicy = mustPolicy("[A-Za-z0-9\u3040-\u309F\u30A0-\u30FF\u4E00-\u9FAF]+", 3, 12, MeasureCharacters)
+
+var regionPolicies = map[string]Policy{
+	"GMS": mustPolicy("[A-Za-z0-9]+", 3, 12, MeasureCharacters),
+	"JMS": mustPolicy("[A-Za-z0-9\u3040-\u309F\u30A0-\u30FF\u4E00-\u9FAF]+", 3, 12, MeasureEncodedBytes),
+	"KMS": mustPolicy("[A-Za-z0-9\uAC00-\uD7A3]+", 3, 12, MeasureEncodedBytes),
+}
+
+// ForRegion returns the default policy for a region. Unknown regions receive a permissive alphanumeric and Japanese
+// policy of 3 to 12 characters.
+func ForRegion(region string) Policy {
+	if p, ok := regionPolicies[region]; ok {
+		return p
+	}
+	return defaultPolicy
+}
@@ -0,0 +1,89 @@
+package name_test
+
+import (
+	"atlas-character/character/name"
+	"github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"testing"
+)
+
+func TestPolicyAnchored(t *testing.T) {
+	p := name.ForRegion("GMS")
+	for _, n := range []string{"Atlas!", "Atlas Two", "ThisNameIsTooLong", "At", "アトラス"} {
+		if p.Valid(n) {
+			t.Fatalf("Name [%s] should be rejected.", n)
+		}
+	}
+	for _, n := range []string{"Atl", "Atlas", "Atlas1234567"} {
+		if !p.Valid(n) {
+			t.Fatalf("Name [%s] should be accepted.", n)
+		}
+	}
+}
+
+func TestPolicyEncodedBytes(t *testing.T) {
+	p := name.ForRegion("KMS")
+	if p.Length("아틀라스") != 8 {
+		t.Fatalf("Length should be 8 bytes, was %d", p.Length("아틀라스"))
+	}
+	if !p.Valid("아틀라스") {
+		t.Fatalf("Name of 4 Hangul syllables should be accepted.")
+	}
+	if !p.Valid("아틀라스ab12") {
+		t.Fatalf("Name of 12 bytes should be accepted.")
+	}
+	if p.Valid("아틀라스아틀라스") {
+		t.Fatalf("Name of 16 bytes should be rejected.")
+	}
+	if p.Valid("아") {
+		t.Fatalf("Name of 2 bytes should be rejected.")
+	}
+	if p.Valid("アトラス") {
+		t.Fatalf("Japanese name should be rejected in KMS.")
+	}
+}
+
+func TestPolicyJapanese(t *testing.T) {
+	p := name.ForRegion("JMS")
+	if !p.Valid("アトラス") {
+		t.Fatalf("Katakana name should be accepted in JMS.")
+	}
+	if p.Valid("アトラスアトラス") {
+		t.Fatalf("Name of 16 bytes should be rejected.")
+	}
+}
+
+func TestPolicyCharacters(t *testing.T) {
+	p, err := name.NewPolicy("[A-Za-z\\x{AC00}-\\x{D7A3}]+", 2, 4, name.MeasureCharacters)
+	if err != nil {
+		t.Fatalf("Failed to create policy: %v", err)
+	}
+	if p.Length("아틀라스") != 4 {
+		t.Fatalf("Length should be 4 characters, was %d", p.Length("아틀라스"))
+	}
+	if !p.Valid("아틀라스") {
+		t.Fatalf("Name of 4 characters should be accepted.")
+	}
+}
+
+func TestNewPolicyInvalid(t *testing.T) {
+	if _, err := name.NewPolicy("[A-Z", 1, 2, name.MeasureCharacters); err == nil {
+		t.Fatalf("Expected invalid pattern to be rejected.")
+	}
+	if _, err := name.NewPolicy("[A-Z]+", 1, 2, "words"); err == nil {
+		t.Fatalf("Expected invalid measure to be rejected.")
+	}
+}
+
+func TestRegistryOverride(t *testing.T) {
+	tt, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+	if name.GetRegistry().Get(tt).Valid("Ab") {
+		t.Fatalf("Region policy should reject a 2 character name.")
+	}
+
+	p, _ := name.NewPolicy("[A-Za-z]+", 2, 6, name.MeasureCharacters)
+	name.GetRegistry().Add(tt.Id(), p)
+	if !name.GetRegistry().Get(tt).Valid("Ab") {
+		t.Fatalf("Tenant policy should accept a 2 character name.")
+	}
+}
@@ -0,0 +1,42 @@
+package name
+
+import (
+	"github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"sync"
+)
+
+type registry struct {
+	policies map[uuid.UUID]Policy
+	mutex    *sync.RWMutex
+}
+
+var once sync.Once
+var reg *registry
+
+func GetRegistry() *registry {
+	once.Do(func() {
+		reg = &registry{
+			policies: make(map[uuid.UUID]Policy),
+			mutex:    &sync.RWMutex{},
+		}
+	})
+	return reg
+}
+
+// Add overrides the region policy for a tenant.
+func (r *registry) Add(tenantId uuid.UUID, p Policy) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.policies[tenantId] = p
+}
+
+// Get returns the policy configured for the tenant, falling back to the policy of the tenant's region.
+func (r *registry) Get(t tenant.Model) Policy {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if p, ok := r.policies[t.Id()]; ok {
+		return p
+	}
+	return ForRegion(t.Region())
+}
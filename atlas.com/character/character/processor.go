@@ -1,7 +1,9 @@
 package character
 
 import (
+	name2 "atlas-character/character/name"
 	"atlas-character/equipable"
+	"atlas-character/equipable/statistics"
 	"atlas-character/equipment"
 	"atlas-character/equipment/slot"
 	"atlas-character/inventory"
@@ -13,11 +15,14 @@ import (
 	"github.com/Chronicle20/atlas-tenant"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
-	"regexp"
 )
 
 var blockedNameErr = errors.New("blocked name")
 var invalidLevelErr = errors.New("invalid level")
+var nameTakenErr = errors.New("name taken")
+
+// ErrInvalidStarterItem is returned when a character is created with a starter item belonging to no inventory.
+var ErrInvalidStarterItem = errors.New("invalid starter item")
 
 // entityModelMapper A function which maps an entity provider to a Model provider
 type entityModelMapper = func(provider model.Provider[entity]) model.Provider[Model]
@@ -93,6 +98,20 @@ func GetForMapInWorld(db *gorm.DB) func(ctx context.Context) func(worldId byte,
 	}
 }
 
+func byTenant(db *gorm.DB, tenant tenant.Model) model.Provider[[]Model] {
+	return entitySliceModelMapperFunc(getForTenant(tenant.Id())(db))(model.ParallelMap())
+}
+
+// GetAll retrieves every character of the tenant.
+func GetAll(db *gorm.DB) func(ctx context.Context) func(decorators ...model.Decorator[Model]) ([]Model, error) {
+	return func(ctx context.Context) func(decorators ...model.Decorator[Model]) ([]Model, error) {
+		return func(decorators ...model.Decorator[Model]) ([]Model, error) {
+			t := tenant.MustFromContext(ctx)
+			return model.SliceMap(model.Decorate[Model](decorators))(byTenant(db, t))(model.ParallelMap())()
+		}
+	}
+}
+
 type NameProvider = func(string) model.Provider[[]Model]
 
 type NameRetriever = func(string) ([]Model, error)
@@ -135,6 +154,26 @@ func InventoryModelDecorator(l logrus.FieldLogger) func(db *gorm.DB) func(ctx co
 	}
 }
 
+// EquipmentModelMapper attaches the equipment worn by a character. Unlike InventoryModelDecorator, it fails should the
+// equipment, or the statistics of any of it, not be retrieved, rather than leave the character without them.
+func EquipmentModelMapper(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(m Model) (Model, error) {
+	return func(db *gorm.DB) func(ctx context.Context) func(m Model) (Model, error) {
+		return func(ctx context.Context) func(m Model) (Model, error) {
+			return func(m Model) (Model, error) {
+				invId, err := inventory.GetInventoryIdByType(db)(ctx)(m.Id(), inventory.TypeValueEquip)()
+				if err != nil {
+					return m, err
+				}
+				es, err := model.Fold(equipable.WornProvider(l)(db)(ctx)(invId), model.FixedProvider(m.GetEquipment()), FoldEquipable)()
+				if err != nil {
+					return m, err
+				}
+				return CloneModel(m).SetEquipment(es).Build(), nil
+			}
+		}
+	}
+}
+
 func FoldEquipable(m equipment.Model, e equipable.Model) (equipment.Model, error) {
 	var setter equipment.SlotSetter
 	if e.Slot() > -100 {
@@ -227,16 +266,12 @@ func IsValidName(_ logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Contex
 	return func(db *gorm.DB) func(ctx context.Context) func(name string) (bool, error) {
 		return func(ctx context.Context) func(name string) (bool, error) {
 			return func(name string) (bool, error) {
-				m, err := regexp.MatchString("[A-Za-z0-9\u3040-\u309F\u30A0-\u30FF\u4E00-\u9FAF]{3,12}", name)
-				if err != nil {
-					return false, err
-				}
-				if !m {
+				if !isValidNameFormat(ctx)(name) {
 					return false, nil
 				}
 
-				cs, err := GetForName(db)(ctx)(name)
-				if len(cs) != 0 || err != nil {
+				taken, err := isNameTaken(db)(ctx)(name)
+				if taken || err != nil {
 					return false, nil
 				}
 
@@ -253,50 +288,121 @@ func IsValidName(_ logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Contex
 	}
 }
 
-func Create(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(input Model) (Model, error) {
-	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(input Model) (Model, error) {
-		return func(ctx context.Context) func(eventProducer producer.Provider) func(input Model) (Model, error) {
-			return func(eventProducer producer.Provider) func(input Model) (Model, error) {
-				return func(input Model) (Model, error) {
+func isValidNameFormat(ctx context.Context) func(name string) bool {
+	return func(name string) bool {
+		return name2.GetRegistry().Get(tenant.MustFromContext(ctx)).Valid(name)
+	}
+}
 
-					ok, err := IsValidName(l)(db)(ctx)(input.Name())
-					if err != nil {
-						l.WithError(err).Errorf("Error validating name [%s] during character creation.", input.Name())
-						return Model{}, err
-					}
-					if !ok {
-						l.Infof("Attempting to create a character with an invalid name [%s].", input.Name())
-						return Model{}, blockedNameErr
-					}
-					if input.Level() < 1 || input.Level() > 200 {
-						l.Infof("Attempting to create character with an invalid level [%d].", input.Level())
-						return Model{}, invalidLevelErr
-					}
+func isNameTaken(db *gorm.DB) func(ctx context.Context) func(name string) (bool, error) {
+	return func(ctx context.Context) func(name string) (bool, error) {
+		return func(name string) (bool, error) {
+			cs, err := GetForName(db)(ctx)(name)
+			if err != nil {
+				return false, err
+			}
+			return len(cs) != 0, nil
+		}
+	}
+}
+
+func Create(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(input Model, items ...uint32) (Model, error) {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(input Model, items ...uint32) (Model, error) {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(input Model, items ...uint32) (Model, error) {
+			return func(eventProducer producer.Provider) func(input Model, items ...uint32) (Model, error) {
+				return CreateWithSlotMax(l)(db)(ctx)(eventProducer)(inventory.ItemSlotMaxProvider(l)(ctx))
+			}
+		}
+	}
+}
 
-					t := tenant.MustFromContext(ctx)
-					var res Model
-					err = db.Transaction(func(tx *gorm.DB) error {
-						res, err = create(tx, t.Id(), input.accountId, input.worldId, input.name, input.level, input.strength, input.dexterity, input.intelligence, input.luck, input.maxHp, input.maxMp, input.jobId, input.gender, input.hair, input.face, input.skinColor, input.mapId)
+// CreateWithSlotMax creates a character as Create does, stacking starter items up to the slot max slotMaxLookup gives.
+func CreateWithSlotMax(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup inventory.SlotMaxLookup) func(input Model, items ...uint32) (Model, error) {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup inventory.SlotMaxLookup) func(input Model, items ...uint32) (Model, error) {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup inventory.SlotMaxLookup) func(input Model, items ...uint32) (Model, error) {
+			return func(eventProducer producer.Provider) func(slotMaxLookup inventory.SlotMaxLookup) func(input Model, items ...uint32) (Model, error) {
+				return func(slotMaxLookup inventory.SlotMaxLookup) func(input Model, items ...uint32) (Model, error) {
+					return func(input Model, items ...uint32) (Model, error) {
+
+						if !isValidNameFormat(ctx)(input.Name()) {
+							l.Infof("Attempting to create a character with an invalid name [%s].", input.Name())
+							return Model{}, blockedNameErr
+						}
+						taken, err := isNameTaken(db)(ctx)(input.Name())
 						if err != nil {
-							l.WithError(err).Errorf("Error persisting character in database.")
-							tx.Rollback()
-							return err
+							l.WithError(err).Errorf("Error validating name [%s] during character creation.", input.Name())
+							return Model{}, err
+						}
+						if taken {
+							l.Infof("Attempting to create a character with a name [%s] which is already taken.", input.Name())
+							return Model{}, nameTakenErr
 						}
+						if input.Level() < 1 || input.Level() > 200 {
+							l.Infof("Attempting to create character with an invalid level [%d].", input.Level())
+							return Model{}, invalidLevelErr
+						}
+
+						// Starter items are announced, and their generated statistics kept, only once the character is committed.
+						t := tenant.MustFromContext(ctx)
+						var res Model
+						events := producer.NewBuffer()
+						generated := make([]uint32, 0)
+						sc := statistics.Tracked(statistics.Create(l)(ctx), &generated)
+						err = db.Transaction(func(tx *gorm.DB) error {
+							res, err = create(tx, t.Id(), input.accountId, input.worldId, input.name, input.level, input.strength, input.dexterity, input.intelligence, input.luck, input.maxHp, input.maxMp, input.jobId, input.gender, input.hair, input.face, input.skinColor, input.mapId)
+							if errors.Is(err, gorm.ErrDuplicatedKey) {
+								l.Infof("Character name [%s] was taken by a concurrent creation.", input.Name())
+								return nameTakenErr
+							}
+							if err != nil {
+								l.WithError(err).Errorf("Error persisting character in database.")
+								tx.Rollback()
+								return err
+							}
+
+							inv, err := inventory.Create(l)(tx)(ctx)(res.id, inventory.DefaultCapacity)
+							if err != nil {
+								l.WithError(err).Errorf("Unable to create inventory for character during character creation.")
+								tx.Rollback()
+								return err
+							}
+							for _, itemId := range items {
+								it, ok := inventory.GetInventoryType(itemId)
+								if !ok {
+									l.Errorf("Unable to determine inventory type for starter item [%d].", itemId)
+									return ErrInvalidStarterItem
+								}
+								err = inventory.CreateItemWithStatistics(l)(tx)(ctx)(events.Provider())(slotMaxLookup)(sc)(res.id, inventory.Type(it), itemId, 1)
+								if err != nil {
+									l.WithError(err).Errorf("Unable to grant starter item [%d] to character [%d] during character creation.", itemId, res.id)
+									return err
+								}
+							}
+
+							if len(items) > 0 {
+								inv, err = inventory.GetInventories(l)(tx)(ctx)(res.id)
+								if err != nil {
+									l.WithError(err).Errorf("Unable to retrieve inventory for character [%d] during character creation.", res.id)
+									return err
+								}
+							}
+							res = CloneModel(res).SetInventory(inv).Build()
+							return nil
+						})
 
-						inv, err := inventory.Create(l)(tx)(ctx)(res.id, 24)
 						if err != nil {
-							l.WithError(err).Errorf("Unable to create inventory for character during character creation.")
-							tx.Rollback()
-							return err
+							statistics.Discard(l, ctx)(generated)
+							return res, err
 						}
-						res = CloneModel(res).SetInventory(inv).Build()
-						return nil
-					})
 
-					if err == nil {
+						err = events.Flush(eventProducer)
+						if err != nil {
+							l.WithError(err).Errorf("Unable to convey starter items of character [%d].", res.Id())
+							return res, err
+						}
 						err = eventProducer(EnvEventTopicCharacterStatus)(createdEventProvider(res.Id(), res.WorldId(), res.Name()))
+						return res, err
 					}
-					return res, err
 				}
 			}
 		}
@@ -509,3 +615,54 @@ func updateTemporal(characterId uint32) model.Operator[MovementSummary] {
 		return nil
 	}
 }
+
+// WearerProvider supplies the level, job, gender and equipment inclusive statistics of a character equipping an item. It
+// fails should the character's equipment not be retrieved, rather than validate against statistics missing its bonuses.
+func WearerProvider(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(characterId uint32) model.Provider[equipment.Wearer] {
+	return func(db *gorm.DB) func(ctx context.Context) func(characterId uint32) model.Provider[equipment.Wearer] {
+		return func(ctx context.Context) func(characterId uint32) model.Provider[equipment.Wearer] {
+			return func(characterId uint32) model.Provider[equipment.Wearer] {
+				return func() (equipment.Wearer, error) {
+					c, err := GetById(db)(ctx)()(characterId)
+					if err != nil {
+						return equipment.Wearer{}, err
+					}
+					c, err = EquipmentModelMapper(l)(db)(ctx)(c)
+					if err != nil {
+						return equipment.Wearer{}, err
+					}
+					worn := make(map[int16]equipment.Bonus)
+					for _, sm := range c.GetEquipment().Slots() {
+						for _, e := range []*equipable.Model{sm.Equipable, sm.CashEquipable} {
+							if e != nil {
+								worn[e.Slot()] = equipment.Bonus{
+									Strength:     uint32(e.Strength()),
+									Dexterity:    uint32(e.Dexterity()),
+									Intelligence: uint32(e.Intelligence()),
+									Luck:         uint32(e.Luck()),
+								}
+							}
+						}
+					}
+					s := ComputeStatistics(c)
+					return equipment.Wearer{
+						Level:        c.Level(),
+						JobId:        c.JobId(),
+						Gender:       c.Gender(),
+						Strength:     s.Strength(),
+						Dexterity:    s.Dexterity(),
+						Intelligence: s.Intelligence(),
+						Luck:         s.Luck(),
+						Worn:         worn,
+					}, nil
+				}
+			}
+		}
+	}
+}
+
+// PositionProvider supplies the last position a character was known to be at on its map.
+func PositionProvider(characterId uint32) model.Provider[inventory.Position] {
+	td := GetTemporalRegistry().GetById(characterId)
+	return model.FixedProvider(inventory.Position{X: td.X(), Y: td.Y()})
+}
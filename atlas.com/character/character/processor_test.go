@@ -2,11 +2,14 @@ package character_test
 
 import (
 	"atlas-character/character"
+	"atlas-character/database"
 	"atlas-character/equipable"
+	"atlas-character/equipable/statistics"
 	"atlas-character/inventory"
 	"atlas-character/inventory/item"
 	"atlas-character/kafka/producer"
 	"context"
+	"errors"
 	producer2 "github.com/Chronicle20/atlas-kafka/producer"
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/Chronicle20/atlas-tenant"
@@ -20,10 +23,13 @@ import (
 )
 
 func testDatabase(t *testing.T) *gorm.DB {
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
 	if err != nil {
 		t.Fatalf("Failed to connect to database: %v", err)
 	}
+	if err := database.RegisterTenantCallbacks(db); err != nil {
+		t.Fatalf("Failed to register tenant scoping: %v", err)
+	}
 
 	var migrators []func(db *gorm.DB) error
 	migrators = append(migrators, character.Migration, inventory.Migration, item.Migration, equipable.Migration)
@@ -61,6 +67,16 @@ func testProducer(output *[]kafka.Message) producer.Provider {
 	}
 }
 
+func testSlotMaxLookup(slotMax uint32) inventory.SlotMaxLookup {
+	return func(characterId uint32) func(itemId uint32) inventory.SlotMaxProvider {
+		return func(itemId uint32) inventory.SlotMaxProvider {
+			return func() (uint32, error) {
+				return slotMax, nil
+			}
+		}
+	}
+}
+
 func TestCreateSunny(t *testing.T) {
 	tctx := tenant.WithContext(context.Background(), testTenant())
 
@@ -68,7 +84,7 @@ func TestCreateSunny(t *testing.T) {
 
 	var outputMessages = make([]kafka.Message, 0)
 
-	c, err := character.Create(testLogger())(testDatabase(t))(tctx)(testProducer(&outputMessages))(input)
+	c, err := character.Create(testLogger())(testDatabase(t).WithContext(tctx))(tctx)(testProducer(&outputMessages))(input)
 	if err != nil {
 		t.Fatalf("Failed to create model: %v", err)
 	}
@@ -91,3 +107,188 @@ func TestCreateSunny(t *testing.T) {
 		t.Fatalf("Number of output messages should be 1, was %d", len(outputMessages))
 	}
 }
+
+func TestCreateWithStarterItems(t *testing.T) {
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).Build()
+
+	var outputMessages = make([]kafka.Message, 0)
+
+	c, err := character.CreateWithSlotMax(testLogger())(testDatabase(t).WithContext(tctx))(tctx)(testProducer(&outputMessages))(testSlotMaxLookup(100))(input, 2000000, 2000000, 4161001)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	use := c.GetInventory().Useable().Items()
+	if len(use) != 1 {
+		t.Fatalf("Useable inventory should contain 1 item, was %d", len(use))
+	}
+	if use[0].ItemId() != 2000000 || use[0].Quantity() != 2 {
+		t.Fatalf("Useable item should be 2 of [2000000], was %d of [%d]", use[0].Quantity(), use[0].ItemId())
+	}
+	etc := c.GetInventory().Etc().Items()
+	if len(etc) != 1 || etc[0].ItemId() != 4161001 {
+		t.Fatalf("Etc inventory should contain item [4161001]")
+	}
+}
+
+func TestCreateWithInvalidStarterItem(t *testing.T) {
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).Build()
+
+	var outputMessages = make([]kafka.Message, 0)
+
+	db := testDatabase(t)
+	_, err := character.Create(testLogger())(db.WithContext(tctx))(tctx)(testProducer(&outputMessages))(input, 9000000)
+	if !errors.Is(err, character.ErrInvalidStarterItem) {
+		t.Fatalf("Expected character creation to fail with an invalid starter item.")
+	}
+
+	cs, err := character.GetForName(db.WithContext(tctx))(tctx)("Atlas")
+	if err != nil {
+		t.Fatalf("Failed to retrieve characters by name: %v", err)
+	}
+	if len(cs) != 0 {
+		t.Fatalf("Character should not have been persisted, found %d", len(cs))
+	}
+}
+
+func TestCreateWithFailingStarterItemConveysNothing(t *testing.T) {
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).Build()
+
+	var outputMessages = make([]kafka.Message, 0)
+
+	db := testDatabase(t)
+	_, err := character.CreateWithSlotMax(testLogger())(db.WithContext(tctx))(tctx)(testProducer(&outputMessages))(testSlotMaxLookup(100))(input, 2000000, 9000000)
+	if err == nil {
+		t.Fatalf("Expected character creation to fail with an invalid starter item.")
+	}
+	if len(outputMessages) != 0 {
+		t.Fatalf("Expected no events for a character which was rolled back, found %d", len(outputMessages))
+	}
+
+	cs, err := character.GetForName(db.WithContext(tctx))(tctx)("Atlas")
+	if err != nil {
+		t.Fatalf("Failed to retrieve characters by name: %v", err)
+	}
+	if len(cs) != 0 {
+		t.Fatalf("Character should not have been persisted, found %d", len(cs))
+	}
+}
+
+func TestCreateNameTaken(t *testing.T) {
+	tctx := tenant.WithContext(context.Background(), testTenant())
+	db := testDatabase(t)
+
+	var outputMessages = make([]kafka.Message, 0)
+
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).Build()
+	_, err := character.Create(testLogger())(db.WithContext(tctx))(tctx)(testProducer(&outputMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	input = character.NewModelBuilder().SetAccountId(1001).SetWorldId(0).SetName("ATLAS").SetLevel(1).Build()
+	_, err = character.Create(testLogger())(db.WithContext(tctx))(tctx)(testProducer(&outputMessages))(input)
+	if err == nil {
+		t.Fatalf("Expected creation of a character with a taken name to fail.")
+	}
+
+	otherCtx := tenant.WithContext(context.Background(), testTenant())
+	_, err = character.Create(testLogger())(db.WithContext(otherCtx))(otherCtx)(testProducer(&outputMessages))(input)
+	if err != nil {
+		t.Fatalf("Name should be available in another tenant: %v", err)
+	}
+}
+
+func TestNameUniqueIndex(t *testing.T) {
+	tctx := tenant.WithContext(context.Background(), testTenant())
+	db := testDatabase(t)
+
+	var outputMessages = make([]kafka.Message, 0)
+
+	for _, name := range []string{"Atlas", "Beta"} {
+		input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName(name).SetLevel(1).Build()
+		_, err := character.Create(testLogger())(db.WithContext(tctx))(tctx)(testProducer(&outputMessages))(input)
+		if err != nil {
+			t.Fatalf("Failed to create model: %v", err)
+		}
+	}
+
+	err := db.Exec("UPDATE characters SET name = ? WHERE name = ?", "atlas", "Beta").Error
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("Expected database to reject a case-insensitive duplicate name, got: %v", err)
+	}
+}
+
+func TestCreateInvalidName(t *testing.T) {
+	tctx := tenant.WithContext(context.Background(), testTenant())
+	db := testDatabase(t)
+
+	var outputMessages = make([]kafka.Message, 0)
+
+	for _, n := range []string{"Atlas!", "AtlasAtlasAtlas", "At"} {
+		input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName(n).SetLevel(1).Build()
+		_, err := character.Create(testLogger())(db.WithContext(tctx))(tctx)(testProducer(&outputMessages))(input)
+		if err == nil {
+			t.Fatalf("Expected creation of a character named [%s] to fail.", n)
+		}
+	}
+}
+
+func TestEquipmentModelMapper(t *testing.T) {
+	t.Setenv("EQUIPABLE_STATISTICS_STORE", statistics.StoreLocal)
+	l := testLogger()
+	db := testDatabase(t)
+	if err := statistics.Migration(db); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	tt := testTenant()
+	tctx := tenant.WithContext(context.Background(), tt)
+
+	var outputMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).SetStrength(4).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&outputMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	equipId, err := inventory.GetInventoryIdByType(db.WithContext(tctx))(tctx)(c.Id(), inventory.TypeValueEquip)()
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	err = db.Exec("INSERT INTO equipables (tenant_id, inventory_id, item_id, slot, reference_id) VALUES (?, ?, ?, ?, ?)", tt.Id(), equipId, 1040002, -5, 7).Error
+	if err != nil {
+		t.Fatalf("Failed to create equipable: %v", err)
+	}
+
+	_, err = character.EquipmentModelMapper(l)(db.WithContext(tctx))(tctx)(c)
+	if !errors.Is(err, equipable.ErrStatisticsUnavailable) {
+		t.Fatalf("Expected equipment without statistics to be unavailable, got %v.", err)
+	}
+
+	err = db.Exec("INSERT INTO equipable_statistics (tenant_id, id, item_id, strength, dexterity, intelligence, luck, hp, mp, weapon_attack, magic_attack, weapon_defense, magic_defense, accuracy, avoidability, hands, speed, jump, slots) VALUES (?, ?, ?, ?, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)", tt.Id(), 7, 1040002, 3).Error
+	if err != nil {
+		t.Fatalf("Failed to create statistics: %v", err)
+	}
+
+	c, err = character.EquipmentModelMapper(l)(db.WithContext(tctx))(tctx)(c)
+	if err != nil {
+		t.Fatalf("Failed to get equipment: %v", err)
+	}
+	if s := character.ComputeStatistics(c); s.Strength() != 7 {
+		t.Fatalf("Strength should be 7, was %d.", s.Strength())
+	}
+
+	w, err := character.WearerProvider(l)(db.WithContext(tctx))(tctx)(c.Id())()
+	if err != nil {
+		t.Fatalf("Failed to get wearer: %v", err)
+	}
+	if w.Strength != 7 || w.Without(-5).Strength != 4 {
+		t.Fatalf("Strength should be 7, and 4 without the top, was %d and %d.", w.Strength, w.Without(-5).Strength)
+	}
+}
@@ -26,6 +26,12 @@ func getForMapInWorld(tenantId uuid.UUID, worldId byte, mapId uint32) database.E
 	}
 }
 
+func getForTenant(tenantId uuid.UUID) database.EntityProvider[[]entity] {
+	return func(db *gorm.DB) model.Provider[[]entity] {
+		return database.SliceQuery[entity](db, &entity{TenantId: tenantId})
+	}
+}
+
 func getForName(tenantId uuid.UUID, name string) database.EntityProvider[[]entity] {
 	return func(db *gorm.DB) model.Provider[[]entity] {
 		var results []entity
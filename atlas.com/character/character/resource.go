@@ -1,11 +1,14 @@
 package character
 
 import (
+	"atlas-character/configuration"
+	"atlas-character/equipable"
 	"atlas-character/kafka/producer"
 	"atlas-character/rest"
 	"errors"
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/Chronicle20/atlas-rest/server"
+	"github.com/Chronicle20/atlas-tenant"
 	"github.com/gorilla/mux"
 	"github.com/manyminds/api2go/jsonapi"
 	"github.com/sirupsen/logrus"
@@ -22,6 +25,7 @@ const (
 	GetCharacter                   = "get_character"
 	DeleteCharacter                = "delete_character"
 	CreateCharacter                = "create_character"
+	GetCharacterStatistics         = "get_character_statistics"
 )
 
 func InitResource(si jsonapi.ServerInformation) func(db *gorm.DB) server.RouteInitializer {
@@ -35,9 +39,10 @@ func InitResource(si jsonapi.ServerInformation) func(db *gorm.DB) server.RouteIn
 			r.HandleFunc("", registerGet(GetCharactersByMap, handleGetCharactersByMap)).Methods(http.MethodGet).Queries("worldId", "{worldId}", "mapId", "{mapId}")
 			r.HandleFunc("", registerGet(GetCharactersByName, handleGetCharactersByName)).Methods(http.MethodGet).Queries("name", "{name}", "include", "{include}")
 			r.HandleFunc("", registerGet(GetCharactersByName, handleGetCharactersByName)).Methods(http.MethodGet).Queries("name", "{name}")
-			r.HandleFunc("", rest.RegisterInputHandler[RestModel](l)(db)(si)(CreateCharacter, handleCreateCharacter)).Methods(http.MethodPost)
+			r.HandleFunc("", rest.RegisterInputHandler[CreateRestModel](l)(db)(si)(CreateCharacter, handleCreateCharacter)).Methods(http.MethodPost)
 			r.HandleFunc("/{characterId}", registerGet(GetCharacter, handleGetCharacter)).Methods(http.MethodGet).Queries("include", "{include}")
 			r.HandleFunc("/{characterId}", registerGet(GetCharacter, handleGetCharacter)).Methods(http.MethodGet)
+			r.HandleFunc("/{characterId}/stats", registerGet(GetCharacterStatistics, handleGetCharacterStatistics)).Methods(http.MethodGet)
 			r.HandleFunc("/{characterId}", rest.RegisterHandler(l)(db)(si)(DeleteCharacter, handleDeleteCharacter)).Methods(http.MethodDelete)
 		}
 	}
@@ -172,20 +177,77 @@ func handleGetCharacter(d *rest.HandlerDependency, c *rest.HandlerContext) http.
 	})
 }
 
-func handleCreateCharacter(d *rest.HandlerDependency, c *rest.HandlerContext, input RestModel) http.HandlerFunc {
+func handleGetCharacterStatistics(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+	return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			cs, err := GetById(d.DB())(d.Context())()(characterId)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Getting character %d.", characterId)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			// Statistics computed without the character's equipment would pass for those of a character wearing none.
+			cs, err = EquipmentModelMapper(d.Logger())(d.DB())(d.Context())(cs)
+			if errors.Is(err, equipable.ErrStatisticsUnavailable) {
+				d.Logger().WithError(err).Errorf("Getting equipment of character %d.", characterId)
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Getting equipment of character %d.", characterId)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			res, err := TransformStatistics(characterId)(ComputeStatistics(cs))
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Creating REST model.")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			server.Marshal[StatisticsRestModel](d.Logger())(w)(c.ServerInformation())(res)
+		}
+	})
+}
+
+func handleCreateCharacter(d *rest.HandlerDependency, c *rest.HandlerContext, input CreateRestModel) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		m, err := Extract(input)
+		t := tenant.MustFromContext(d.Context())
+		tc, err := configuration.Get().FindTenant(t.Id())
 		if err != nil {
-			d.Logger().WithError(err).Errorf("Creating model.")
+			d.Logger().WithError(err).Errorf("Unable to locate configuration for tenant [%s].", t.Id().String())
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
-		cs, err := Create(d.Logger())(d.DB())(d.Context())(producer.ProviderImpl(d.Logger())(d.Context()))(m)
+		ct, err := tc.FindTemplate(input.JobId, input.Gender)
 		if err != nil {
-			if errors.Is(err, blockedNameErr) || errors.Is(err, invalidLevelErr) {
+			d.Logger().WithError(err).Errorf("No character template for job [%d] and gender [%d].", input.JobId, input.Gender)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		m, items, err := FromTemplate(ct, input)
+		if err != nil {
+			d.Logger().WithError(err).Errorf("Character creation choices are not permitted by template for job [%d] and gender [%d].", input.JobId, input.Gender)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		cs, err := Create(d.Logger())(d.DB())(d.Context())(producer.ProviderImpl(d.Logger())(d.Context()))(m, items...)
+		if err != nil {
+			if errors.Is(err, blockedNameErr) || errors.Is(err, invalidLevelErr) || errors.Is(err, ErrInvalidStarterItem) {
 				w.WriteHeader(http.StatusBadRequest)
 				return
 			}
+			if errors.Is(err, nameTakenErr) {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
 
 			d.Logger().WithError(err).Errorf("Creating character.")
 			w.WriteHeader(http.StatusInternalServerError)
@@ -153,3 +153,113 @@ func Extract(m RestModel) (Model, error) {
 		inventory:          inv,
 	}, nil
 }
+
+// CreateRestModel is the input for character creation. Stats, job, and starting map are dictated by the tenant's
+// creation template; the request only makes appearance and equipment choices from that template.
+type CreateRestModel struct {
+	Id        uint32 `json:"-"`
+	AccountId uint32 `json:"accountId"`
+	WorldId   byte   `json:"worldId"`
+	Name      string `json:"name"`
+	JobId     uint16 `json:"jobId"`
+	Gender    byte   `json:"gender"`
+	Face      uint32 `json:"face"`
+	Hair      uint32 `json:"hair"`
+	SkinColor byte   `json:"skinColor"`
+	Top       uint32 `json:"top"`
+	Bottom    uint32 `json:"bottom"`
+	Shoes     uint32 `json:"shoes"`
+	Weapon    uint32 `json:"weapon"`
+}
+
+func (r CreateRestModel) GetName() string {
+	return "characters"
+}
+
+func (r CreateRestModel) GetID() string {
+	return strconv.Itoa(int(r.Id))
+}
+
+func (r *CreateRestModel) SetID(strId string) error {
+	id, err := strconv.Atoi(strId)
+	if err != nil {
+		return err
+	}
+	r.Id = uint32(id)
+	return nil
+}
+
+type StatisticsRestModel struct {
+	Id               uint32 `json:"-"`
+	BaseStrength     uint16 `json:"baseStrength"`
+	BaseDexterity    uint16 `json:"baseDexterity"`
+	BaseIntelligence uint16 `json:"baseIntelligence"`
+	BaseLuck         uint16 `json:"baseLuck"`
+	BaseMaxHp        uint16 `json:"baseMaxHp"`
+	BaseMaxMp        uint16 `json:"baseMaxMp"`
+	Strength         uint32 `json:"strength"`
+	Dexterity        uint32 `json:"dexterity"`
+	Intelligence     uint32 `json:"intelligence"`
+	Luck             uint32 `json:"luck"`
+	MaxHp            uint32 `json:"maxHp"`
+	MaxMp            uint32 `json:"maxMp"`
+	WeaponAttack     uint32 `json:"weaponAttack"`
+	MagicAttack      uint32 `json:"magicAttack"`
+	WeaponDefense    uint32 `json:"weaponDefense"`
+	MagicDefense     uint32 `json:"magicDefense"`
+	Accuracy         uint32 `json:"accuracy"`
+	Avoidability     uint32 `json:"avoidability"`
+	Hands            uint32 `json:"hands"`
+	Speed            uint32 `json:"speed"`
+	Jump             uint32 `json:"jump"`
+	MinDamage        uint32 `json:"minDamage"`
+	MaxDamage        uint32 `json:"maxDamage"`
+}
+
+func (r StatisticsRestModel) GetName() string {
+	return "statistics"
+}
+
+func (r StatisticsRestModel) GetID() string {
+	return strconv.Itoa(int(r.Id))
+}
+
+func (r *StatisticsRestModel) SetID(strId string) error {
+	id, err := strconv.Atoi(strId)
+	if err != nil {
+		return err
+	}
+	r.Id = uint32(id)
+	return nil
+}
+
+func TransformStatistics(characterId uint32) func(s Statistics) (StatisticsRestModel, error) {
+	return func(s Statistics) (StatisticsRestModel, error) {
+		return StatisticsRestModel{
+			Id:               characterId,
+			BaseStrength:     s.BaseStrength(),
+			BaseDexterity:    s.BaseDexterity(),
+			BaseIntelligence: s.BaseIntelligence(),
+			BaseLuck:         s.BaseLuck(),
+			BaseMaxHp:        s.BaseMaxHp(),
+			BaseMaxMp:        s.BaseMaxMp(),
+			Strength:         s.Strength(),
+			Dexterity:        s.Dexterity(),
+			Intelligence:     s.Intelligence(),
+			Luck:             s.Luck(),
+			MaxHp:            s.MaxHp(),
+			MaxMp:            s.MaxMp(),
+			WeaponAttack:     s.WeaponAttack(),
+			MagicAttack:      s.MagicAttack(),
+			WeaponDefense:    s.WeaponDefense(),
+			MagicDefense:     s.MagicDefense(),
+			Accuracy:         s.Accuracy(),
+			Avoidability:     s.Avoidability(),
+			Hands:            s.Hands(),
+			Speed:            s.Speed(),
+			Jump:             s.Jump(),
+			MinDamage:        s.MinDamage(),
+			MaxDamage:        s.MaxDamage(),
+		}, nil
+	}
+}
@@ -0,0 +1,212 @@
+package character
+
+import (
+	"atlas-character/equipable"
+	"atlas-character/inventory/item"
+	"atlas-character/job"
+	"math"
+)
+
+// baseMastery is the weapon mastery of a character without mastery skills.
+const baseMastery = 0.1
+
+// Statistics are a character's base statistics combined with the bonuses of everything they have equipped.
+type Statistics struct {
+	baseStrength     uint16
+	baseDexterity    uint16
+	baseIntelligence uint16
+	baseLuck         uint16
+	baseMaxHp        uint16
+	baseMaxMp        uint16
+	strength         uint32
+	dexterity        uint32
+	intelligence     uint32
+	luck             uint32
+	maxHp            uint32
+	maxMp            uint32
+	weaponAttack     uint32
+	magicAttack      uint32
+	weaponDefense    uint32
+	magicDefense     uint32
+	accuracy         uint32
+	avoidability     uint32
+	hands            uint32
+	speed            uint32
+	jump             uint32
+	minDamage        uint32
+	maxDamage        uint32
+}
+
+func (s Statistics) BaseStrength() uint16 {
+	return s.baseStrength
+}
+
+func (s Statistics) BaseDexterity() uint16 {
+	return s.baseDexterity
+}
+
+func (s Statistics) BaseIntelligence() uint16 {
+	return s.baseIntelligence
+}
+
+func (s Statistics) BaseLuck() uint16 {
+	return s.baseLuck
+}
+
+func (s Statistics) BaseMaxHp() uint16 {
+	return s.baseMaxHp
+}
+
+func (s Statistics) BaseMaxMp() uint16 {
+	return s.baseMaxMp
+}
+
+func (s Statistics) Strength() uint32 {
+	return s.strength
+}
+
+func (s Statistics) Dexterity() uint32 {
+	return s.dexterity
+}
+
+func (s Statistics) Intelligence() uint32 {
+	return s.intelligence
+}
+
+func (s Statistics) Luck() uint32 {
+	return s.luck
+}
+
+func (s Statistics) MaxHp() uint32 {
+	return s.maxHp
+}
+
+func (s Statistics) MaxMp() uint32 {
+	return s.maxMp
+}
+
+func (s Statistics) WeaponAttack() uint32 {
+	return s.weaponAttack
+}
+
+func (s Statistics) MagicAttack() uint32 {
+	return s.magicAttack
+}
+
+func (s Statistics) WeaponDefense() uint32 {
+	return s.weaponDefense
+}
+
+func (s Statistics) MagicDefense() uint32 {
+	return s.magicDefense
+}
+
+func (s Statistics) Accuracy() uint32 {
+	return s.accuracy
+}
+
+func (s Statistics) Avoidability() uint32 {
+	return s.avoidability
+}
+
+func (s Statistics) Hands() uint32 {
+	return s.hands
+}
+
+func (s Statistics) Speed() uint32 {
+	return s.speed
+}
+
+func (s Statistics) Jump() uint32 {
+	return s.jump
+}
+
+func (s Statistics) MinDamage() uint32 {
+	return s.minDamage
+}
+
+func (s Statistics) MaxDamage() uint32 {
+	return s.maxDamage
+}
+
+// ComputeStatistics totals the base statistics of the character with the bonuses of their equipment. The character
+// must be decorated with its equipment.
+func ComputeStatistics(m Model) Statistics {
+	s := Statistics{
+		baseStrength:     m.Strength(),
+		baseDexterity:    m.Dexterity(),
+		baseIntelligence: m.Intelligence(),
+		baseLuck:         m.Luck(),
+		baseMaxHp:        m.MaxHP(),
+		baseMaxMp:        m.MaxMP(),
+		strength:         uint32(m.Strength()),
+		dexterity:        uint32(m.Dexterity()),
+		intelligence:     uint32(m.Intelligence()),
+		luck:             uint32(m.Luck()),
+		maxHp:            uint32(m.MaxHP()),
+		maxMp:            uint32(m.MaxMP()),
+		speed:            100,
+		jump:             100,
+	}
+
+	for _, sm := range m.GetEquipment().Slots() {
+		for _, e := range []*equipable.Model{sm.Equipable, sm.CashEquipable} {
+			if e != nil {
+				s = s.addEquipable(*e)
+			}
+		}
+	}
+
+	var weaponId uint32
+	if w := m.GetEquipment().Weapon().Equipable; w != nil {
+		weaponId = w.ItemId()
+	}
+	s.minDamage, s.maxDamage = damageRange(m.JobId(), weaponId, s)
+	return s
+}
+
+func (s Statistics) addEquipable(e equipable.Model) Statistics {
+	s.strength += uint32(e.Strength())
+	s.dexterity += uint32(e.Dexterity())
+	s.intelligence += uint32(e.Intelligence())
+	s.luck += uint32(e.Luck())
+	s.maxHp += uint32(e.HP())
+	s.maxMp += uint32(e.MP())
+	s.weaponAttack += uint32(e.WeaponAttack())
+	s.magicAttack += uint32(e.MagicAttack())
+	s.weaponDefense += uint32(e.WeaponDefense())
+	s.magicDefense += uint32(e.MagicDefense())
+	s.accuracy += uint32(e.Accuracy())
+	s.avoidability += uint32(e.Avoidability())
+	s.hands += uint32(e.Hands())
+	s.speed += uint32(e.Speed())
+	s.jump += uint32(e.Jump())
+	return s
+}
+
+// damageRange computes the physical damage range for the weapon wielded.
+func damageRange(jobId uint16, weaponId uint32, s Statistics) (uint32, uint32) {
+	weaponType := item.GetWeaponType(weaponId)
+	if weaponType == item.WeaponTypeInvalid {
+		return 0, 0
+	}
+	if weaponType == item.WeaponTypeDaggerOther && job.IsA(jobId, job.Thief, job.NightWalker1) {
+		weaponType = item.WeaponTypeDaggerThieves
+	}
+
+	var primary, secondary float64
+	switch weaponType {
+	case item.WeaponTypeBow, item.WeaponTypeCrossbow, item.WeaponTypeGun:
+		primary, secondary = float64(s.dexterity), float64(s.strength)
+	case item.WeaponTypeClaw, item.WeaponTypeDaggerThieves:
+		primary, secondary = float64(s.luck), float64(s.strength+s.dexterity)
+	default:
+		primary, secondary = float64(s.strength), float64(s.dexterity)
+	}
+
+	multiplier := item.GetWeaponDamageMultiplier(weaponType)
+	watk := float64(s.weaponAttack) / 100
+	maxDamage := (primary*multiplier + secondary) * watk
+	minDamage := (primary*multiplier*0.9*baseMastery + secondary) * watk
+	return uint32(math.Floor(minDamage)), uint32(math.Floor(maxDamage))
+}
@@ -0,0 +1,42 @@
+package character_test
+
+import (
+	"atlas-character/character"
+	"atlas-character/equipable"
+	"atlas-character/equipment"
+	"testing"
+)
+
+func TestComputeStatistics(t *testing.T) {
+	weapon := equipable.NewModelBuilder().SetItemId(1302000).SetSlot(-11).SetWeaponAttack(17).Build()
+	top := equipable.NewModelBuilder().SetItemId(1040002).SetSlot(-5).SetStrength(3).SetHP(10).SetSpeed(5).Build()
+	eqp := equipment.NewModel().SetWeapon(&weapon).SetTop(&top)
+
+	c := character.NewModelBuilder().SetJobId(100).SetStrength(20).SetDexterity(10).SetIntelligence(4).SetLuck(4).SetMaxHp(50).SetMaxMp(5).SetEquipment(eqp).Build()
+
+	s := character.ComputeStatistics(c)
+	if s.BaseStrength() != 20 || s.Strength() != 23 {
+		t.Fatalf("Strength should be 20 base and 23 total, was %d and %d", s.BaseStrength(), s.Strength())
+	}
+	if s.MaxHp() != 60 || s.MaxMp() != 5 {
+		t.Fatalf("Max HP/MP should be 60/5, was %d/%d", s.MaxHp(), s.MaxMp())
+	}
+	if s.WeaponAttack() != 17 {
+		t.Fatalf("Weapon attack should be 17, was %d", s.WeaponAttack())
+	}
+	if s.Speed() != 105 || s.Jump() != 100 {
+		t.Fatalf("Speed/jump should be 105/100, was %d/%d", s.Speed(), s.Jump())
+	}
+	if s.MinDamage() != 3 || s.MaxDamage() != 17 {
+		t.Fatalf("Damage range should be 3 ~ 17, was %d ~ %d", s.MinDamage(), s.MaxDamage())
+	}
+}
+
+func TestComputeStatisticsUnarmed(t *testing.T) {
+	c := character.NewModelBuilder().SetStrength(12).SetDexterity(5).SetEquipment(equipment.NewModel()).Build()
+
+	s := character.ComputeStatistics(c)
+	if s.MinDamage() != 0 || s.MaxDamage() != 0 {
+		t.Fatalf("Damage range should be 0 ~ 0 without a weapon, was %d ~ %d", s.MinDamage(), s.MaxDamage())
+	}
+}
@@ -0,0 +1,91 @@
+package character
+
+import (
+	"atlas-character/configuration"
+	"atlas-character/equipment/slot/information"
+	"atlas-character/equipment/statistics"
+	"atlas-character/gamedata"
+	item "atlas-character/inventory/item/information"
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"slices"
+)
+
+var invalidTemplateChoiceErr = errors.New("choice not permitted by character template")
+
+// FromTemplate produces the Model to create and the starter items to grant, given a creation template and the choices
+// made in a creation request. Every choice must be one offered by the template.
+func FromTemplate(t configuration.CharacterTemplate, input CreateRestModel) (Model, []uint32, error) {
+	if !slices.Contains(t.Faces, input.Face) {
+		return Model{}, nil, invalidTemplateChoiceErr
+	}
+	if !slices.Contains(t.Hairs, input.Hair-input.Hair%10) || !slices.Contains(t.HairColors, input.Hair%10) {
+		return Model{}, nil, invalidTemplateChoiceErr
+	}
+	if !slices.Contains(t.SkinColors, input.SkinColor) {
+		return Model{}, nil, invalidTemplateChoiceErr
+	}
+
+	items := make([]uint32, 0)
+	for _, c := range []struct {
+		choices []uint32
+		choice  uint32
+	}{{t.Tops, input.Top}, {t.Bottoms, input.Bottom}, {t.Shoes, input.Shoes}, {t.Weapons, input.Weapon}} {
+		if len(c.choices) == 0 && c.choice == 0 {
+			continue
+		}
+		if !slices.Contains(c.choices, c.choice) {
+			return Model{}, nil, invalidTemplateChoiceErr
+		}
+		items = append(items, c.choice)
+	}
+	items = append(items, t.Items...)
+
+	m := NewModelBuilder().
+		SetAccountId(input.AccountId).
+		SetWorldId(input.WorldId).
+		SetName(input.Name).
+		SetLevel(1).
+		SetJobId(t.JobId).
+		SetGender(t.Gender).
+		SetFace(input.Face).
+		SetHair(input.Hair).
+		SetSkinColor(input.SkinColor).
+		SetStrength(t.Strength).
+		SetDexterity(t.Dexterity).
+		SetIntelligence(t.Intelligence).
+		SetLuck(t.Luck).
+		SetHp(t.MaxHp).
+		SetMaxHp(t.MaxHp).
+		SetMp(t.MaxMp).
+		SetMaxMp(t.MaxMp).
+		SetMapId(t.MapId).
+		Build()
+	return m, items, nil
+}
+
+// TemplateWarmer retrieves the game data of the equipment and items the tenant's character templates grant, which every
+// character creation needs.
+func TemplateWarmer(c configuration.CharacterConfiguration) gamedata.Warmer {
+	return func(l logrus.FieldLogger, ctx context.Context) error {
+		var errs []error
+		for _, t := range c.Templates {
+			for _, itemId := range slices.Concat(t.Tops, t.Bottoms, t.Shoes, t.Weapons, t.Items) {
+				if itemId/1000000 != 1 {
+					if _, err := item.GetById(l, ctx)(itemId); err != nil {
+						errs = append(errs, err)
+					}
+					continue
+				}
+				if _, err := information.GetById(l, ctx)(itemId); err != nil {
+					errs = append(errs, err)
+				}
+				if _, err := statistics.GetById(l, ctx)(itemId); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
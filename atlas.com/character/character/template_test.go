@@ -0,0 +1,70 @@
+package character_test
+
+import (
+	"atlas-character/character"
+	"atlas-character/configuration"
+	"testing"
+)
+
+func testTemplate() configuration.CharacterTemplate {
+	return configuration.CharacterTemplate{
+		JobId:        1000,
+		Gender:       0,
+		MapId:        130030000,
+		Strength:     12,
+		Dexterity:    5,
+		Intelligence: 4,
+		Luck:         4,
+		MaxHp:        50,
+		MaxMp:        5,
+		Faces:        []uint32{20000, 20001},
+		Hairs:        []uint32{30000, 30020},
+		HairColors:   []uint32{0, 7},
+		SkinColors:   []byte{0, 1},
+		Tops:         []uint32{1042167},
+		Bottoms:      []uint32{1062115},
+		Shoes:        []uint32{1072383},
+		Weapons:      []uint32{1302000},
+		Items:        []uint32{4161047},
+	}
+}
+
+func TestFromTemplate(t *testing.T) {
+	input := character.CreateRestModel{AccountId: 1000, WorldId: 1, Name: "Atlas", JobId: 1000, Face: 20001, Hair: 30027, SkinColor: 1, Top: 1042167, Bottom: 1062115, Shoes: 1072383, Weapon: 1302000}
+
+	m, items, err := character.FromTemplate(testTemplate(), input)
+	if err != nil {
+		t.Fatalf("Failed to apply template: %v", err)
+	}
+	if m.JobId() != 1000 || m.MapId() != 130030000 || m.Level() != 1 {
+		t.Fatalf("Job, map, and level should come from template.")
+	}
+	if m.Strength() != 12 || m.Dexterity() != 5 || m.Intelligence() != 4 || m.Luck() != 4 || m.MaxHP() != 50 || m.MaxMP() != 5 {
+		t.Fatalf("Stats should come from template.")
+	}
+	if m.Hair() != 30027 || m.Face() != 20001 || m.SkinColor() != 1 {
+		t.Fatalf("Appearance should come from input.")
+	}
+	if len(items) != 5 {
+		t.Fatalf("Should grant 5 starter items, was %d", len(items))
+	}
+}
+
+func TestFromTemplateInvalidChoice(t *testing.T) {
+	valid := character.CreateRestModel{Name: "Atlas", Face: 20000, Hair: 30000, SkinColor: 0, Top: 1042167, Bottom: 1062115, Shoes: 1072383, Weapon: 1302000}
+
+	for name, mutate := range map[string]func(*character.CreateRestModel){
+		"face":      func(i *character.CreateRestModel) { i.Face = 21000 },
+		"hairStyle": func(i *character.CreateRestModel) { i.Hair = 30030 },
+		"hairColor": func(i *character.CreateRestModel) { i.Hair = 30003 },
+		"skinColor": func(i *character.CreateRestModel) { i.SkinColor = 9 },
+		"top":       func(i *character.CreateRestModel) { i.Top = 1040002 },
+		"weapon":    func(i *character.CreateRestModel) { i.Weapon = 0 },
+	} {
+		input := valid
+		mutate(&input)
+		if _, _, err := character.FromTemplate(testTemplate(), input); err == nil {
+			t.Fatalf("Expected [%s] choice to be rejected.", name)
+		}
+	}
+}
@@ -26,9 +26,10 @@ func Get() *Configuration {
 }
 
 type Configuration struct {
-	UseStarting4Ap          bool    `yaml:"useStarting4Ap"`
-	UseAutoAssignStartersAp bool    `yaml:"useAutoAssignStartersAp"`
-	MaxAp                   uint16  `yaml:"maxAp"`
-	UseRandomizeHpMpGain    bool    `yaml:"useRandomizeHpMpGain"`
-	UseEnforceJobSpRange    bool    `yaml:"useEnforceJobSpRange"`
+	UseStarting4Ap          bool                  `yaml:"useStarting4Ap"`
+	UseAutoAssignStartersAp bool                  `yaml:"useAutoAssignStartersAp"`
+	MaxAp                   uint16                `yaml:"maxAp"`
+	UseRandomizeHpMpGain    bool                  `yaml:"useRandomizeHpMpGain"`
+	UseEnforceJobSpRange    bool                  `yaml:"useEnforceJobSpRange"`
+	Tenants                 []TenantConfiguration `yaml:"tenants"`
 }
@@ -0,0 +1,83 @@
+package configuration
+
+import (
+	"errors"
+	"github.com/google/uuid"
+)
+
+var ErrTenantNotFound = errors.New("tenant configuration not found")
+var ErrTemplateNotFound = errors.New("character template not found")
+
+type TenantConfiguration struct {
+	Id string `yaml:"id"`
+	// Region, MajorVersion and MinorVersion identify the tenant's game data, which is retrieved ahead of use on start-up
+	// when they are set.
+	Region       string                 `yaml:"region"`
+	MajorVersion uint16                 `yaml:"majorVersion"`
+	MinorVersion uint16                 `yaml:"minorVersion"`
+	Characters   CharacterConfiguration `yaml:"characters"`
+}
+
+type CharacterConfiguration struct {
+	Templates []CharacterTemplate `yaml:"templates"`
+	Names     *NameConfiguration  `yaml:"names"`
+	// LockedEquipmentSlots are equipment slots unavailable in the tenant's version, such as ring slots which are only
+	// unlocked by quests. They are locked for every character of the tenant, including those having unlocked them.
+	LockedEquipmentSlots []int16 `yaml:"lockedEquipmentSlots"`
+	// MaxInventoryCapacity is the most slots an inventory may be expanded to in the tenant's version.
+	MaxInventoryCapacity uint32 `yaml:"maxInventoryCapacity"`
+}
+
+// NameConfiguration overrides the region's character name rules. Pattern describes the permitted characters and must
+// match the whole name. Measure is either "characters" or "bytes".
+type NameConfiguration struct {
+	Pattern   string `yaml:"pattern"`
+	MinLength int    `yaml:"minLength"`
+	MaxLength int    `yaml:"maxLength"`
+	Measure   string `yaml:"measure"`
+}
+
+// CharacterTemplate describes what a newly created character of a given job family and gender starts with, and which
+// appearance and equipment choices a creation request may make.
+type CharacterTemplate struct {
+	JobId        uint16   `yaml:"jobId"`
+	Gender       byte     `yaml:"gender"`
+	MapId        uint32   `yaml:"mapId"`
+	Strength     uint16   `yaml:"strength"`
+	Dexterity    uint16   `yaml:"dexterity"`
+	Intelligence uint16   `yaml:"intelligence"`
+	Luck         uint16   `yaml:"luck"`
+	MaxHp        uint16   `yaml:"maxHp"`
+	MaxMp        uint16   `yaml:"maxMp"`
+	Faces        []uint32 `yaml:"faces"`
+	Hairs        []uint32 `yaml:"hairs"`
+	HairColors   []uint32 `yaml:"hairColors"`
+	SkinColors   []byte   `yaml:"skinColors"`
+	Tops         []uint32 `yaml:"tops"`
+	Bottoms      []uint32 `yaml:"bottoms"`
+	Shoes        []uint32 `yaml:"shoes"`
+	Weapons      []uint32 `yaml:"weapons"`
+	Items        []uint32 `yaml:"items"`
+}
+
+func (c *Configuration) FindTenant(tenantId uuid.UUID) (TenantConfiguration, error) {
+	for _, t := range c.Tenants {
+		id, err := uuid.Parse(t.Id)
+		if err != nil {
+			continue
+		}
+		if id == tenantId {
+			return t, nil
+		}
+	}
+	return TenantConfiguration{}, ErrTenantNotFound
+}
+
+func (t TenantConfiguration) FindTemplate(jobId uint16, gender byte) (CharacterTemplate, error) {
+	for _, ct := range t.Characters.Templates {
+		if ct.JobId == jobId && ct.Gender == gender {
+			return ct, nil
+		}
+	}
+	return CharacterTemplate{}, ErrTemplateNotFound
+}
@@ -0,0 +1,50 @@
+package consistency
+
+import (
+	"atlas-character/kafka/producer"
+	"context"
+	"encoding/json"
+	"flag"
+	"github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"io"
+)
+
+// CommandName is the argument which runs the service as a one-shot inventory consistency check.
+const CommandName = "check-inventories"
+
+// RunCommand checks the inventories of the tenant named by args and writes the report to out as JSON.
+//
+//	check-inventories -tenant <id> -region <region> -major <version> -minor <version> [-repair]
+func RunCommand(l logrus.FieldLogger, db *gorm.DB, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(CommandName, flag.ContinueOnError)
+	tenantId := fs.String("tenant", "", "id of the tenant whose characters are checked")
+	region := fs.String("region", "GMS", "region of the tenant")
+	major := fs.Uint("major", 83, "major version of the tenant")
+	minor := fs.Uint("minor", 1, "minor version of the tenant")
+	repair := fs.Bool("repair", false, "relocate misplaced assets and recreate missing inventories")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(*tenantId)
+	if err != nil {
+		return err
+	}
+	t, err := tenant.Create(id, *region, uint16(*major), uint16(*minor))
+	if err != nil {
+		return err
+	}
+	ctx := tenant.WithContext(context.Background(), t)
+
+	res, err := Check(l)(db.WithContext(ctx))(ctx)(producer.ProviderImpl(l)(ctx))(StatisticsReferenceChecker(l)(ctx))(*repair)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
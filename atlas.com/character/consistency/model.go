@@ -0,0 +1,35 @@
+package consistency
+
+import "atlas-character/inventory"
+
+type Kind string
+
+const (
+	KindDuplicateSlot     Kind = "DUPLICATE_SLOT"
+	KindBeyondCapacity    Kind = "BEYOND_CAPACITY"
+	KindInvalidSlot       Kind = "INVALID_SLOT"
+	KindOrphanedEquipable Kind = "ORPHANED_EQUIPABLE"
+	KindMissingInventory  Kind = "MISSING_INVENTORY"
+)
+
+// Report is the outcome of scanning a tenant's inventories.
+type Report struct {
+	TenantId   string    `json:"tenantId"`
+	Characters int       `json:"characters"`
+	Repair     bool      `json:"repair"`
+	Anomalies  []Anomaly `json:"anomalies"`
+}
+
+// Anomaly is a single inconsistency found in a character's inventory. AssetId identifies the offending item or
+// equipable, and is absent for missing inventories.
+type Anomaly struct {
+	Kind          Kind           `json:"kind"`
+	CharacterId   uint32         `json:"characterId"`
+	InventoryType inventory.Type `json:"inventoryType"`
+	AssetId       uint32         `json:"assetId,omitempty"`
+	ItemId        uint32         `json:"itemId,omitempty"`
+	Slot          int16          `json:"slot,omitempty"`
+	ReferenceId   uint32         `json:"referenceId,omitempty"`
+	Repaired      bool           `json:"repaired"`
+	Resolution    string         `json:"resolution,omitempty"`
+}
@@ -0,0 +1,367 @@
+package consistency
+
+import (
+	"atlas-character/character"
+	"atlas-character/equipable"
+	"atlas-character/equipable/statistics"
+	"atlas-character/equipment/slot"
+	"atlas-character/inventory"
+	"atlas-character/inventory/item"
+	"atlas-character/kafka/producer"
+	"atlas-character/rest"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Chronicle20/atlas-tenant"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"sort"
+)
+
+// ReferenceChecker reports whether the generated statistics an equipable references still exist, failing should that
+// not be determined. Statistics stored locally are read through db. References are resolved before a repair begins,
+// never within its transaction.
+type ReferenceChecker func(db *gorm.DB) func(referenceId uint32) (bool, error)
+
+// StatisticsReferenceChecker looks references up in the equipable statistics store. Only statistics the store reports
+// not to exist are missing references; any other failure to retrieve them fails the check, rather than report the
+// equipable as orphaned.
+func StatisticsReferenceChecker(l logrus.FieldLogger) func(ctx context.Context) ReferenceChecker {
+	return func(ctx context.Context) ReferenceChecker {
+		return func(db *gorm.DB) func(referenceId uint32) (bool, error) {
+			return func(referenceId uint32) (bool, error) {
+				_, err := statistics.GetById(l, db, ctx)(referenceId)
+				if errors.Is(err, gorm.ErrRecordNotFound) || rest.IsNotFound(err) {
+					return false, nil
+				}
+				if err != nil {
+					l.WithError(err).Errorf("Unable to determine whether statistics [%d] exist.", referenceId)
+					return false, err
+				}
+				return true, nil
+			}
+		}
+	}
+}
+
+// Check scans the inventories of every character of the tenant for anomalies. When repair is set, misplaced assets are
+// relocated to free slots and missing inventories are recreated. Each character is repaired in its own transaction, so
+// a failed repair leaves that character untouched and is recorded in the report. Relocations are conveyed through
+// eventProducer as moves.
+func Check(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(referenceChecker ReferenceChecker) func(repair bool) (Report, error) {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(referenceChecker ReferenceChecker) func(repair bool) (Report, error) {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(referenceChecker ReferenceChecker) func(repair bool) (Report, error) {
+			return func(eventProducer producer.Provider) func(referenceChecker ReferenceChecker) func(repair bool) (Report, error) {
+				return func(referenceChecker ReferenceChecker) func(repair bool) (Report, error) {
+					return func(repair bool) (Report, error) {
+						t := tenant.MustFromContext(ctx)
+						r := Report{TenantId: t.Id().String(), Repair: repair, Anomalies: make([]Anomaly, 0)}
+
+						cs, err := character.GetAll(db)(ctx)()
+						if err != nil {
+							l.WithError(err).Errorf("Unable to retrieve characters to check.")
+							return r, err
+						}
+
+						for _, c := range cs {
+							exists, err := references(db)(ctx)(referenceChecker)(c.Id())
+							if err != nil {
+								l.WithError(err).Errorf("Unable to resolve equipment references of character [%d].", c.Id())
+								return r, err
+							}
+
+							var as []Anomaly
+							if !repair {
+								as, err = checkCharacter(l)(db)(ctx)(producer.NewBuffer().Provider())(exists)(false)(c.Id())
+								if err != nil {
+									l.WithError(err).Errorf("Unable to check inventories of character [%d].", c.Id())
+									return r, err
+								}
+							} else {
+								as, err = repairCharacter(l)(db)(ctx)(eventProducer)(exists)(c.Id())
+								if err != nil {
+									l.WithError(err).Errorf("Unable to repair inventories of character [%d].", c.Id())
+									as = failedRepair(as, err)
+								}
+							}
+							r.Characters += 1
+							r.Anomalies = append(r.Anomalies, as...)
+						}
+						l.Infof("Checked [%d] characters, found [%d] anomalies.", r.Characters, len(r.Anomalies))
+						return r, nil
+					}
+				}
+			}
+		}
+	}
+}
+
+// references resolves whether the statistics referenced by the equipables of a character exist. This is done before
+// any repair, so that no statistics are retrieved while a repair transaction is open. The references of equipables
+// created since are assumed to exist.
+func references(db *gorm.DB) func(ctx context.Context) func(referenceChecker ReferenceChecker) func(characterId uint32) (func(referenceId uint32) bool, error) {
+	return func(ctx context.Context) func(referenceChecker ReferenceChecker) func(characterId uint32) (func(referenceId uint32) bool, error) {
+		return func(referenceChecker ReferenceChecker) func(characterId uint32) (func(referenceId uint32) bool, error) {
+			return func(characterId uint32) (func(referenceId uint32) bool, error) {
+				resolved := make(map[uint32]bool)
+				exists := func(referenceId uint32) bool {
+					e, ok := resolved[referenceId]
+					return !ok || e
+				}
+
+				inventoryId, err := inventory.GetInventoryIdByType(db)(ctx)(characterId, inventory.TypeValueEquip)()
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return exists, nil
+				}
+				if err != nil {
+					return nil, err
+				}
+				ms, err := equipable.ByInventoryProvider(db)(ctx)(inventoryId)()
+				if err != nil {
+					return nil, err
+				}
+				check := referenceChecker(db)
+				for _, m := range ms {
+					resolved[m.ReferenceId()], err = check(m.ReferenceId())
+					if err != nil {
+						return nil, err
+					}
+				}
+				return exists, nil
+			}
+		}
+	}
+}
+
+// repairCharacter repairs the inventories of a character in a single transaction. The inventory locks taken by the
+// inventory commands are held throughout, so that no command interleaves with the repair, and relocations are only
+// conveyed once the repair commits.
+func repairCharacter(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(exists func(referenceId uint32) bool) func(characterId uint32) ([]Anomaly, error) {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(exists func(referenceId uint32) bool) func(characterId uint32) ([]Anomaly, error) {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(exists func(referenceId uint32) bool) func(characterId uint32) ([]Anomaly, error) {
+			return func(eventProducer producer.Provider) func(exists func(referenceId uint32) bool) func(characterId uint32) ([]Anomaly, error) {
+				return func(exists func(referenceId uint32) bool) func(characterId uint32) ([]Anomaly, error) {
+					return func(characterId uint32) ([]Anomaly, error) {
+						for _, it := range inventory.Types {
+							invLock := inventory.GetLockRegistry().GetById(characterId, it)
+							invLock.Lock()
+							defer invLock.Unlock()
+						}
+
+						events := producer.NewBuffer()
+						var as []Anomaly
+						err := db.Transaction(func(tx *gorm.DB) error {
+							var txErr error
+							as, txErr = checkCharacter(l)(tx)(ctx)(events.Provider())(exists)(true)(characterId)
+							return txErr
+						})
+						if err != nil {
+							return as, err
+						}
+
+						err = events.Flush(eventProducer)
+						if err != nil {
+							l.WithError(err).Errorf("Unable to convey relocated assets of character [%d].", characterId)
+						}
+						return as, nil
+					}
+				}
+			}
+		}
+	}
+}
+
+// failedRepair marks the anomalies of a character whose repair was rolled back as unrepaired.
+func failedRepair(as []Anomaly, err error) []Anomaly {
+	results := make([]Anomaly, 0)
+	for _, a := range as {
+		if a.Kind != KindOrphanedEquipable && (a.Repaired || a.Resolution == "") {
+			a.Repaired = false
+			a.Resolution = fmt.Sprintf("repair failed: %s", err.Error())
+		}
+		results = append(results, a)
+	}
+	return results
+}
+
+func checkCharacter(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(exists func(referenceId uint32) bool) func(repair bool) func(characterId uint32) ([]Anomaly, error) {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(exists func(referenceId uint32) bool) func(repair bool) func(characterId uint32) ([]Anomaly, error) {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(exists func(referenceId uint32) bool) func(repair bool) func(characterId uint32) ([]Anomaly, error) {
+			return func(eventProducer producer.Provider) func(exists func(referenceId uint32) bool) func(repair bool) func(characterId uint32) ([]Anomaly, error) {
+				return func(exists func(referenceId uint32) bool) func(repair bool) func(characterId uint32) ([]Anomaly, error) {
+					return func(repair bool) func(characterId uint32) ([]Anomaly, error) {
+						return func(characterId uint32) ([]Anomaly, error) {
+							relocate := func(update func(id uint32, slot int16) error) slotUpdater {
+								return func(e entry, slot int16) error {
+									err := update(e.id, slot)
+									if err != nil {
+										return err
+									}
+									return eventProducer(inventory.EnvEventInventoryChanged)(inventory.ItemMovedEventProvider(characterId)(e.slot)(e.itemId)(slot))
+								}
+							}
+
+							as := make([]Anomaly, 0)
+							for _, it := range inventory.Types {
+								inventoryId, err := inventory.GetInventoryIdByType(db)(ctx)(characterId, it)()
+								if errors.Is(err, gorm.ErrRecordNotFound) {
+									a := Anomaly{Kind: KindMissingInventory, CharacterId: characterId, InventoryType: it}
+									if repair {
+										err = inventory.CreateType(l)(db)(ctx)(characterId, it, inventory.DefaultCapacity)
+										if err != nil {
+											return append(as, a), err
+										}
+										a.Repaired = true
+										a.Resolution = "inventory created"
+									}
+									as = append(as, a)
+									continue
+								}
+								if err != nil {
+									return as, err
+								}
+
+								capacity, err := inventory.GetCapacity(db)(ctx)(inventoryId)()
+								if err != nil {
+									return as, err
+								}
+
+								var es []entry
+								var updater slotUpdater
+								var isEquipped func(slot int16) bool
+								if it == inventory.TypeValueEquip {
+									ms, err := equipable.ByInventoryProvider(db)(ctx)(inventoryId)()
+									if err != nil {
+										return as, err
+									}
+									for _, m := range ms {
+										es = append(es, entry{id: m.Id(), itemId: m.ItemId(), slot: m.Slot(), referenceId: m.ReferenceId()})
+									}
+									updater = relocate(equipable.UpdateSlot(db)(ctx))
+									isEquipped = slot.IsEquipmentSlot
+								} else {
+									ms, err := item.GetByInventory(db)(ctx)(inventoryId)
+									if err != nil {
+										return as, err
+									}
+									for _, m := range ms {
+										es = append(es, entry{id: m.Id(), itemId: m.ItemId(), slot: m.Slot()})
+									}
+									updater = relocate(item.UpdateSlot(db)(ctx))
+									isEquipped = func(s int16) bool {
+										return false
+									}
+								}
+
+								ias, err := checkSlots(l)(repair)(updater)(characterId, it, capacity, es, isEquipped)
+								as = append(as, ias...)
+								if err != nil {
+									return as, err
+								}
+								if it == inventory.TypeValueEquip {
+									as = append(as, checkReferences(exists)(characterId, es)...)
+								}
+							}
+							return as, nil
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// entry is an item or equipable as far as slot placement is concerned.
+type entry struct {
+	id          uint32
+	itemId      uint32
+	slot        int16
+	referenceId uint32
+}
+
+// slotUpdater relocates an asset to slot.
+type slotUpdater func(e entry, slot int16) error
+
+// checkSlots finds assets in invalid slots, beyond the inventory's capacity, or sharing a slot with another asset. Of
+// assets sharing a slot, the one created first keeps it. When repairing, the misplaced assets are moved to the lowest
+// free slots.
+func checkSlots(l logrus.FieldLogger) func(repair bool) func(updater slotUpdater) func(characterId uint32, inventoryType inventory.Type, capacity uint32, es []entry, isEquipped func(slot int16) bool) ([]Anomaly, error) {
+	return func(repair bool) func(updater slotUpdater) func(characterId uint32, inventoryType inventory.Type, capacity uint32, es []entry, isEquipped func(slot int16) bool) ([]Anomaly, error) {
+		return func(updater slotUpdater) func(characterId uint32, inventoryType inventory.Type, capacity uint32, es []entry, isEquipped func(slot int16) bool) ([]Anomaly, error) {
+			return func(characterId uint32, inventoryType inventory.Type, capacity uint32, es []entry, isEquipped func(slot int16) bool) ([]Anomaly, error) {
+				sort.SliceStable(es, func(i, j int) bool {
+					if es[i].slot != es[j].slot {
+						return es[i].slot < es[j].slot
+					}
+					return es[i].id < es[j].id
+				})
+
+				as := make([]Anomaly, 0)
+				occupied := make(map[int16]bool)
+				for _, e := range es {
+					var kind Kind
+					switch {
+					case e.slot <= 0 && !isEquipped(e.slot):
+						kind = KindInvalidSlot
+					case e.slot > 0 && capacity > 0 && uint32(e.slot) > capacity:
+						kind = KindBeyondCapacity
+					case occupied[e.slot]:
+						kind = KindDuplicateSlot
+					default:
+						occupied[e.slot] = true
+						continue
+					}
+					as = append(as, Anomaly{Kind: kind, CharacterId: characterId, InventoryType: inventoryType, AssetId: e.id, ItemId: e.itemId, Slot: e.slot, ReferenceId: e.referenceId})
+				}
+				if !repair {
+					return as, nil
+				}
+
+				for i := range as {
+					s, ok := freeSlot(occupied, capacity)
+					if !ok {
+						as[i].Resolution = "no free slot"
+						continue
+					}
+					err := updater(entry{id: as[i].AssetId, itemId: as[i].ItemId, slot: as[i].Slot, referenceId: as[i].ReferenceId}, s)
+					if err != nil {
+						l.WithError(err).Errorf("Unable to relocate asset [%d] of character [%d] to slot [%d].", as[i].AssetId, characterId, s)
+						return as, err
+					}
+					occupied[s] = true
+					as[i].Repaired = true
+					as[i].Resolution = fmt.Sprintf("relocated to slot %d", s)
+				}
+				return as, nil
+			}
+		}
+	}
+}
+
+// freeSlot yields the lowest unoccupied slot within capacity. A capacity of 0 is treated as unbounded.
+func freeSlot(occupied map[int16]bool, capacity uint32) (int16, bool) {
+	for s := int16(1); s > 0; s++ {
+		if capacity > 0 && uint32(s) > capacity {
+			return 0, false
+		}
+		if !occupied[s] {
+			return s, true
+		}
+	}
+	return 0, false
+}
+
+// checkReferences finds equipables whose generated statistics no longer exist. These are only reported, as the
+// statistics cannot be recovered.
+func checkReferences(exists func(referenceId uint32) bool) func(characterId uint32, es []entry) []Anomaly {
+	return func(characterId uint32, es []entry) []Anomaly {
+		as := make([]Anomaly, 0)
+		for _, e := range es {
+			if exists(e.referenceId) {
+				continue
+			}
+			as = append(as, Anomaly{Kind: KindOrphanedEquipable, CharacterId: characterId, InventoryType: inventory.TypeValueEquip, AssetId: e.id, ItemId: e.itemId, Slot: e.slot, ReferenceId: e.referenceId})
+		}
+		return as
+	}
+}
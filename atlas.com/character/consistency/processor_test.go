@@ -0,0 +1,309 @@
+package consistency_test
+
+import (
+	"atlas-character/character"
+	"atlas-character/consistency"
+	"atlas-character/database"
+	"atlas-character/equipable"
+	"atlas-character/equipable/statistics"
+	"atlas-character/inventory"
+	"atlas-character/inventory/item"
+	"atlas-character/kafka/producer"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	producer2 "github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"slices"
+	"testing"
+	"time"
+)
+
+func testDatabase(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	if err := database.RegisterTenantCallbacks(db); err != nil {
+		t.Fatalf("Failed to register tenant scoping: %v", err)
+	}
+
+	var migrators []func(db *gorm.DB) error
+	migrators = append(migrators, character.Migration, inventory.Migration, item.Migration, equipable.Migration)
+
+	for _, migrator := range migrators {
+		if err := migrator(db); err != nil {
+			t.Fatalf("Failed to migrate database: %v", err)
+		}
+	}
+	return db
+}
+
+func testTenant() tenant.Model {
+	t, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+	return t
+}
+
+func testLogger() logrus.FieldLogger {
+	l, _ := test.NewNullLogger()
+	return l
+}
+
+func testProducer(output *[]kafka.Message) producer.Provider {
+	return func(token string) producer2.MessageProducer {
+		return func(provider model.Provider[[]kafka.Message]) error {
+			res, err := provider()
+			if err != nil {
+				return err
+			}
+			*output = append(*output, res...)
+			return nil
+		}
+	}
+}
+
+// knownReferences checks references against referenceIds, failing t should a reference be checked within a transaction.
+func knownReferences(t *testing.T, referenceIds ...uint32) consistency.ReferenceChecker {
+	return func(db *gorm.DB) func(referenceId uint32) (bool, error) {
+		return func(referenceId uint32) (bool, error) {
+			if _, ok := db.Statement.ConnPool.(gorm.TxCommitter); ok {
+				t.Errorf("Reference [%d] checked within a transaction.", referenceId)
+			}
+			for _, id := range referenceIds {
+				if id == referenceId {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+	}
+}
+
+func countKind(r consistency.Report, kind consistency.Kind) int {
+	count := 0
+	for _, a := range r.Anomalies {
+		if a.Kind == kind {
+			count += 1
+		}
+	}
+	return count
+}
+
+func TestCheck(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	tt := testTenant()
+	tctx := tenant.WithContext(context.Background(), tt)
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).SetExperience(0).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	// Simulate data predating the slot constraints.
+	err = db.Exec("DROP INDEX idx_items_inventory_slot").Error
+	if err != nil {
+		t.Fatalf("Failed to drop index: %v", err)
+	}
+
+	useId, err := inventory.GetInventoryIdByType(db.WithContext(tctx))(tctx)(c.Id(), inventory.TypeValueUse)()
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	for _, s := range []int16{1, 1, 30} {
+		_, err = item.CreateItemInSlot(db.WithContext(tctx))(tctx)(useId)(2000000, 1, s)
+		if err != nil {
+			t.Fatalf("Failed to create item: %v", err)
+		}
+	}
+
+	equipId, err := inventory.GetInventoryIdByType(db.WithContext(tctx))(tctx)(c.Id(), inventory.TypeValueEquip)()
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	for _, e := range []struct {
+		slot        int16
+		referenceId uint32
+	}{{-5, 1}, {-30, 2}, {1, 3}} {
+		err = db.Exec("INSERT INTO equipables (tenant_id, inventory_id, item_id, slot, reference_id) VALUES (?, ?, ?, ?, ?)", tt.Id(), equipId, 1040002, e.slot, e.referenceId).Error
+		if err != nil {
+			t.Fatalf("Failed to create equipable: %v", err)
+		}
+	}
+
+	err = db.Exec("DELETE FROM inventory WHERE character_id = ? AND inventory_type = ?", c.Id(), inventory.TypeValueETC).Error
+	if err != nil {
+		t.Fatalf("Failed to delete inventory: %v", err)
+	}
+
+	var checkMessages = make([]kafka.Message, 0)
+	r, err := consistency.Check(l)(db.WithContext(tctx))(tctx)(testProducer(&checkMessages))(knownReferences(t, 1, 2))(false)
+	if err != nil {
+		t.Fatalf("Failed to check inventories: %v", err)
+	}
+	if r.Characters != 1 || len(r.Anomalies) != 5 {
+		t.Fatalf("Unexpected report: %v", r)
+	}
+	for _, kind := range []consistency.Kind{consistency.KindDuplicateSlot, consistency.KindBeyondCapacity, consistency.KindInvalidSlot, consistency.KindOrphanedEquipable, consistency.KindMissingInventory} {
+		if countKind(r, kind) != 1 {
+			t.Fatalf("Expected one [%s] anomaly, got: %v", kind, r.Anomalies)
+		}
+	}
+	for _, a := range r.Anomalies {
+		if a.Repaired {
+			t.Fatalf("Anomaly repaired while only checking: %v", a)
+		}
+	}
+	if len(checkMessages) != 0 {
+		t.Fatalf("Expected no events while only checking, got: %v", checkMessages)
+	}
+
+	// A repair waits for inventory commands in progress.
+	useLock := inventory.GetLockRegistry().GetById(c.Id(), inventory.TypeValueUse)
+	useLock.Lock()
+	repaired := make(chan struct{})
+	go func() {
+		defer close(repaired)
+		r, err = consistency.Check(l)(db.WithContext(tctx))(tctx)(testProducer(&checkMessages))(knownReferences(t, 1, 2))(true)
+	}()
+	select {
+	case <-repaired:
+		t.Fatalf("Repair did not wait for the inventory lock.")
+	case <-time.After(100 * time.Millisecond):
+	}
+	useLock.Unlock()
+	<-repaired
+	if err != nil {
+		t.Fatalf("Failed to repair inventories: %v", err)
+	}
+	for _, a := range r.Anomalies {
+		if a.Kind != consistency.KindOrphanedEquipable && !a.Repaired {
+			t.Fatalf("Anomaly not repaired: %v", a)
+		}
+	}
+
+	is, err := item.GetByInventory(db.WithContext(tctx))(tctx)(useId)
+	if err != nil {
+		t.Fatalf("Failed to get items: %v", err)
+	}
+	slots := make(map[int16]bool)
+	for _, i := range is {
+		slots[i.Slot()] = true
+	}
+	if len(slots) != 3 || !slots[1] || !slots[2] || !slots[3] {
+		t.Fatalf("Items not relocated, slots: %v", slots)
+	}
+	_, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), 2)
+	if err != nil {
+		t.Fatalf("Equipable not relocated: %v", err)
+	}
+
+	// Each relocation is conveyed as a move.
+	var moves []string
+	for _, m := range checkMessages {
+		var e struct {
+			Type string `json:"type"`
+			Slot int16  `json:"slot"`
+			Body struct {
+				OldSlot int16 `json:"oldSlot"`
+			} `json:"body"`
+		}
+		err = json.Unmarshal(m.Value, &e)
+		if err != nil {
+			t.Fatalf("Failed to decode inventory changed event: %v", err)
+		}
+		moves = append(moves, fmt.Sprintf("%s:%d:%d", e.Type, e.Body.OldSlot, e.Slot))
+	}
+	expectedMoves := []string{
+		inventory.ChangedTypeMove + ":-30:2",
+		inventory.ChangedTypeMove + ":1:2",
+		inventory.ChangedTypeMove + ":30:3",
+	}
+	if !slices.Equal(moves, expectedMoves) {
+		t.Fatalf("Expected events %v, got: %v", expectedMoves, moves)
+	}
+
+	r, err = consistency.Check(l)(db.WithContext(tctx))(tctx)(testProducer(&checkMessages))(knownReferences(t, 1, 2))(false)
+	if err != nil {
+		t.Fatalf("Failed to check inventories: %v", err)
+	}
+	if len(r.Anomalies) != 1 || r.Anomalies[0].Kind != consistency.KindOrphanedEquipable {
+		t.Fatalf("Expected only the orphaned equipable to remain, got: %v", r.Anomalies)
+	}
+}
+
+func TestStatisticsReferenceChecker(t *testing.T) {
+	t.Setenv("EQUIPABLE_STATISTICS_STORE", statistics.StoreLocal)
+	l := testLogger()
+	db := testDatabase(t)
+	tt := testTenant()
+	tctx := tenant.WithContext(context.Background(), tt)
+	check := consistency.StatisticsReferenceChecker(l)(tctx)(db.WithContext(tctx))
+
+	// The statistics cannot be read at all, which says nothing of whether they exist.
+	_, err := check(1)
+	if err == nil {
+		t.Fatalf("Expected the check to fail when statistics cannot be read.")
+	}
+
+	if err = statistics.Migration(db); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	err = db.Exec("INSERT INTO equipable_statistics (tenant_id, id, item_id, strength, dexterity, intelligence, luck, hp, mp, weapon_attack, magic_attack, weapon_defense, magic_defense, accuracy, avoidability, hands, speed, jump, slots) VALUES (?, ?, ?, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)", tt.Id(), 1, 1040002).Error
+	if err != nil {
+		t.Fatalf("Failed to create statistics: %v", err)
+	}
+	if exists, err := check(1); err != nil || !exists {
+		t.Fatalf("Expected statistics [1] to exist, got %t %v.", exists, err)
+	}
+	if exists, err := check(2); err != nil || exists {
+		t.Fatalf("Expected statistics [2] to be missing, got %t %v.", exists, err)
+	}
+}
+
+func TestCheckReferenceFailure(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	tt := testTenant()
+	tctx := tenant.WithContext(context.Background(), tt)
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+	equipId, err := inventory.GetInventoryIdByType(db.WithContext(tctx))(tctx)(c.Id(), inventory.TypeValueEquip)()
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	err = db.Exec("INSERT INTO equipables (tenant_id, inventory_id, item_id, slot, reference_id) VALUES (?, ?, ?, ?, ?)", tt.Id(), equipId, 1040002, 1, 1).Error
+	if err != nil {
+		t.Fatalf("Failed to create equipable: %v", err)
+	}
+
+	unavailable := errors.New("statistics store unavailable")
+	failing := func(db *gorm.DB) func(referenceId uint32) (bool, error) {
+		return func(referenceId uint32) (bool, error) {
+			return false, unavailable
+		}
+	}
+	var checkMessages = make([]kafka.Message, 0)
+	r, err := consistency.Check(l)(db.WithContext(tctx))(tctx)(testProducer(&checkMessages))(failing)(false)
+	if !errors.Is(err, unavailable) {
+		t.Fatalf("Expected the check to fail, got %v.", err)
+	}
+	if countKind(r, consistency.KindOrphanedEquipable) != 0 {
+		t.Fatalf("Equipables should not be reported orphaned when their statistics could not be checked: %v", r.Anomalies)
+	}
+}
@@ -0,0 +1,52 @@
+package consistency
+
+import (
+	"atlas-character/kafka/producer"
+	"atlas-character/rest"
+	"encoding/json"
+	"github.com/Chronicle20/atlas-rest/server"
+	"github.com/gorilla/mux"
+	"github.com/manyminds/api2go/jsonapi"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+const (
+	checkInventories  = "check_inventories"
+	repairInventories = "repair_inventories"
+)
+
+// InitResource registers the administrative endpoints which check, and repair, the inventories of the requesting
+// tenant's characters.
+func InitResource(si jsonapi.ServerInformation) func(db *gorm.DB) server.RouteInitializer {
+	return func(db *gorm.DB) server.RouteInitializer {
+		return func(router *mux.Router, l logrus.FieldLogger) {
+			register := rest.RegisterHandler(l)(db)(si)
+			r := router.PathPrefix("/inventories/consistency").Subrouter()
+			r.HandleFunc("", register(checkInventories, handleCheck(false))).Methods(http.MethodGet)
+			r.HandleFunc("", register(repairInventories, handleCheck(true))).Methods(http.MethodPost)
+		}
+	}
+}
+
+func handleCheck(repair bool) rest.GetHandler {
+	return func(d *rest.HandlerDependency, _ *rest.HandlerContext) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			rc := StatisticsReferenceChecker(d.Logger())(d.Context())
+			res, err := Check(d.Logger())(d.DB())(d.Context())(producer.ProviderImpl(d.Logger())(d.Context()))(rc)(repair)
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Unable to check inventories.")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			err = json.NewEncoder(w).Encode(res)
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Writing inventory consistency report.")
+			}
+		}
+	}
+}
@@ -2,12 +2,16 @@ package database
 
 import (
 	"atlas-character/retry"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"os"
 	"strconv"
+	"time"
 )
 
 type DSNBuilder struct {
@@ -57,14 +61,32 @@ func (d *DSNBuilder) Build() string {
 	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=UTC", d.host, d.user, d.password, d.databaseName, d.port)
 }
 
+const (
+	DriverPostgres     = "postgres"
+	DriverSQLite       = "sqlite"
+	DriverSQLiteMemory = "sqlite-memory"
+)
+
+var ErrUnknownDriver = errors.New("unknown database driver")
+
 type Configuration struct {
-	dsn        string
-	migrations []Migrator
+	driver            string
+	dsn               string
+	path              string
+	maxOpenConns      int
+	maxIdleConns      int
+	connMaxLifetime   time.Duration
+	connMaxIdleTime   time.Duration
+	connectAttempts   int
+	connectDelay      time.Duration
+	connectMaxDelay   time.Duration
+	connectMaxElapsed time.Duration
+	migrations        []Migration
 }
 
 type Configurator func(c *Configuration)
 
-func SetMigrations(migrations ...Migrator) Configurator {
+func SetMigrations(migrations ...Migration) Configurator {
 	return func(c *Configuration) {
 		c.migrations = migrations
 	}
@@ -72,7 +94,52 @@ func SetMigrations(migrations ...Migrator) Configurator {
 
 type Migrator func(db *gorm.DB) error
 
+// Connect opens the database selected by DB_DRIVER, registers tenant scoping, and applies the configured migrations.
 func Connect(l logrus.FieldLogger, configurators ...Configurator) *gorm.DB {
+	c := configurationFromEnvironment(l)
+	for _, configurator := range configurators {
+		configurator(c)
+	}
+
+	dialector, err := c.dialector()
+	if err != nil {
+		l.WithError(err).Fatalf("Unable to configure database driver [%s].", c.driver)
+	}
+
+	var db *gorm.DB
+	p := retry.MaxElapsed(retry.Exponential(c.connectDelay, c.connectMaxDelay, c.connectAttempts), c.connectMaxElapsed)
+	err = retry.Try(context.Background(), p, func(attempt int) (bool, error) {
+		var oerr error
+		db, oerr = gorm.Open(dialector, &gorm.Config{TranslateError: true})
+		if oerr != nil {
+			l.WithError(oerr).Warnf("Failed to connect to database on attempt [%d].", attempt)
+			return true, oerr
+		}
+		return false, nil
+	})
+	if err != nil {
+		l.WithError(err).Fatalf("Failed to connect to database.")
+	}
+
+	err = c.configurePool(db)
+	if err != nil {
+		l.WithError(err).Fatalf("Failed to configure connection pool.")
+	}
+
+	err = RegisterTenantCallbacks(db)
+	if err != nil {
+		l.WithError(err).Fatalf("Failed to register tenant scoping.")
+	}
+
+	// Migrate the schema
+	_, err = Migrate(l)(db)(c.migrations...)(false)
+	if err != nil {
+		l.WithError(err).Fatalf("Migrating schema.")
+	}
+	return db
+}
+
+func configurationFromEnvironment(l logrus.FieldLogger) *Configuration {
 	dsnBuilder := NewDSNBuilder()
 	user, ok := os.LookupEnv("DB_USER")
 	if ok {
@@ -103,34 +170,96 @@ func Connect(l logrus.FieldLogger, configurators ...Configurator) *gorm.DB {
 	}
 
 	c := &Configuration{
-		dsn:        dsnBuilder.Build(),
-		migrations: make([]Migrator, 0),
+		driver:          DriverPostgres,
+		dsn:             dsnBuilder.Build(),
+		path:            "atlas-character.db",
+		connectAttempts: 10,
+		connectDelay:    time.Second,
+		connectMaxDelay: 30 * time.Second,
+		migrations:      make([]Migration, 0),
 	}
-	for _, configurator := range configurators {
-		configurator(c)
+	if driver, ok := os.LookupEnv("DB_DRIVER"); ok && driver != "" {
+		c.driver = driver
 	}
+	if path, ok := os.LookupEnv("DB_PATH"); ok && path != "" {
+		c.path = path
+	}
+	c.maxOpenConns = intFromEnvironment(l)("DB_MAX_OPEN_CONNS", 0)
+	c.maxIdleConns = intFromEnvironment(l)("DB_MAX_IDLE_CONNS", 2)
+	c.connMaxLifetime = durationFromEnvironment(l)("DB_CONN_MAX_LIFETIME", 0)
+	c.connMaxIdleTime = durationFromEnvironment(l)("DB_CONN_MAX_IDLE_TIME", 0)
+	c.connectAttempts = max(intFromEnvironment(l)("DB_CONNECT_ATTEMPTS", c.connectAttempts), 1)
+	c.connectDelay = durationFromEnvironment(l)("DB_CONNECT_DELAY", c.connectDelay)
+	c.connectMaxDelay = durationFromEnvironment(l)("DB_CONNECT_MAX_DELAY", c.connectMaxDelay)
+	c.connectMaxElapsed = durationFromEnvironment(l)("DB_CONNECT_MAX_ELAPSED", 0)
+	return c
+}
 
-	var db *gorm.DB
-	tryToConnect := func(attempt int) (bool, error) {
-		var err error
-		db, err = gorm.Open(postgres.Open(dsnBuilder.Build()), &gorm.Config{})
-		if err != nil {
-			return true, err
-		}
-		return false, err
+func (c *Configuration) dialector() (gorm.Dialector, error) {
+	switch c.driver {
+	case DriverPostgres:
+		return postgres.Open(c.dsn), nil
+	case DriverSQLite:
+		return sqlite.Open(fmt.Sprintf("file:%s?_foreign_keys=1&_busy_timeout=5000", c.path)), nil
+	case DriverSQLiteMemory:
+		return sqlite.Open("file:atlas-character?mode=memory&cache=shared&_foreign_keys=1"), nil
 	}
+	return nil, ErrUnknownDriver
+}
 
-	err := retry.Try(tryToConnect, 10)
+// configurePool applies the pool tuning. SQLite permits a single writer, so its pool is limited to one connection,
+// which an in-memory database additionally keeps open for its lifetime, as the database is discarded with the last
+// connection.
+func (c *Configuration) configurePool(db *gorm.DB) error {
+	sqlDB, err := db.DB()
 	if err != nil {
-		l.WithError(err).Fatalf("Failed to connect to database.")
+		return err
+	}
+	switch c.driver {
+	case DriverSQLite:
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetConnMaxLifetime(c.connMaxLifetime)
+		sqlDB.SetConnMaxIdleTime(c.connMaxIdleTime)
+	case DriverSQLiteMemory:
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		sqlDB.SetConnMaxIdleTime(0)
+	default:
+		sqlDB.SetMaxOpenConns(c.maxOpenConns)
+		sqlDB.SetMaxIdleConns(c.maxIdleConns)
+		sqlDB.SetConnMaxLifetime(c.connMaxLifetime)
+		sqlDB.SetConnMaxIdleTime(c.connMaxIdleTime)
 	}
+	return nil
+}
 
-	// Migrate the schema
-	for _, m := range c.migrations {
-		err = m(db)
+func intFromEnvironment(l logrus.FieldLogger) func(key string, fallback int) int {
+	return func(key string, fallback int) int {
+		val, ok := os.LookupEnv(key)
+		if !ok || val == "" {
+			return fallback
+		}
+		res, err := strconv.Atoi(val)
 		if err != nil {
-			l.WithError(err).Fatalf("Migrating schema.")
+			l.WithError(err).Warnf("Invalid [%s], using [%d].", key, fallback)
+			return fallback
 		}
+		return res
+	}
+}
+
+func durationFromEnvironment(l logrus.FieldLogger) func(key string, fallback time.Duration) time.Duration {
+	return func(key string, fallback time.Duration) time.Duration {
+		val, ok := os.LookupEnv(key)
+		if !ok || val == "" {
+			return fallback
+		}
+		res, err := time.ParseDuration(val)
+		if err != nil {
+			l.WithError(err).Warnf("Invalid [%s], using [%s].", key, fallback)
+			return fallback
+		}
+		return res
 	}
-	return db
 }
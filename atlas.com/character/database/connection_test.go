@@ -0,0 +1,48 @@
+package database_test
+
+import (
+	"atlas-character/database"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gorm.io/gorm"
+	"sync"
+	"testing"
+)
+
+func TestConnectSQLiteMemory(t *testing.T) {
+	t.Setenv("DB_DRIVER", database.DriverSQLiteMemory)
+	t.Setenv("DB_CONNECT_ATTEMPTS", "1")
+	l, _ := test.NewNullLogger()
+
+	db := database.Connect(l, database.SetMigrations(database.GoMigration(1, "create owned", func(db *gorm.DB) error {
+		return db.AutoMigrate(&owned{})
+	})))
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to retrieve connection pool: %v", err)
+	}
+	defer sqlDB.Close()
+
+	actx, aid := testContext()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(value uint32) {
+			defer wg.Done()
+			if err := db.WithContext(actx).Create(&owned{Value: value}).Error; err != nil {
+				t.Errorf("Failed to create row: %v", err)
+			}
+		}(uint32(i))
+	}
+	wg.Wait()
+
+	var results []owned
+	err = db.WithContext(actx).Find(&results).Error
+	if err != nil || len(results) != 10 {
+		t.Fatalf("Expected every connection to share the database, got: %d %v", len(results), err)
+	}
+	for _, r := range results {
+		if r.TenantId != aid {
+			t.Fatalf("Row not assigned to tenant: %v", r)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// migrationLockKey identifies the advisory lock held while migrating, so that replicas starting concurrently apply
+// migrations one at a time.
+const migrationLockKey = int64(7314660521)
+
+var ErrMigrationOrder = errors.New("migration versions must be unique and ascending")
+
+// Migration is a versioned change to the schema. It is written either in Go, as Up, or as SQL statements separated by
+// semicolons. Once applied, a migration is recorded in the schema_migrations table and never applied again, so applied
+// migrations must not be changed; further changes are made by adding a migration with a higher version.
+type Migration struct {
+	version     uint32
+	description string
+	up          Migrator
+	sql         string
+}
+
+func (m Migration) Version() uint32 {
+	return m.version
+}
+
+func (m Migration) Description() string {
+	return m.description
+}
+
+// GoMigration creates a migration which applies up.
+func GoMigration(version uint32, description string, up Migrator) Migration {
+	return Migration{version: version, description: description, up: up}
+}
+
+// SQLMigration creates a migration which executes the statements in sql.
+func SQLMigration(version uint32, description string, sql string) Migration {
+	return Migration{version: version, description: description, sql: sql}
+}
+
+func (m Migration) apply(db *gorm.DB) error {
+	if m.up != nil {
+		return m.up(db)
+	}
+	for _, s := range strings.Split(m.sql, ";") {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		err := db.Exec(s).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type migrationEntity struct {
+	Version     uint32    `gorm:"primaryKey;autoIncrement:false"`
+	Description string    `gorm:"not null"`
+	AppliedAt   time.Time `gorm:"not null"`
+}
+
+func (e migrationEntity) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrate applies the migrations which have not been applied yet, in version order, and yields them. All migrations
+// are applied in a single transaction, so a failing migration leaves the schema as it was. On postgres the transaction
+// holds an advisory lock, so concurrent callers wait and then find the migrations applied. In a dry run the pending
+// migrations are only yielded. Migrations span every tenant, so they are made WithoutTenant.
+func Migrate(l logrus.FieldLogger) func(db *gorm.DB) func(migrations ...Migration) func(dryRun bool) ([]Migration, error) {
+	return func(db *gorm.DB) func(migrations ...Migration) func(dryRun bool) ([]Migration, error) {
+		db = db.WithContext(WithoutTenant(db.Statement.Context))
+		return func(migrations ...Migration) func(dryRun bool) ([]Migration, error) {
+			return func(dryRun bool) ([]Migration, error) {
+				for i := 1; i < len(migrations); i++ {
+					if migrations[i].version <= migrations[i-1].version {
+						return nil, ErrMigrationOrder
+					}
+				}
+
+				if dryRun {
+					if !db.Migrator().HasTable(&migrationEntity{}) {
+						return migrations, nil
+					}
+					return pendingMigrations(db, migrations)
+				}
+
+				var applied []Migration
+				err := db.Transaction(func(tx *gorm.DB) error {
+					if tx.Dialector.Name() == "postgres" {
+						err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockKey).Error
+						if err != nil {
+							return err
+						}
+					}
+					err := tx.AutoMigrate(&migrationEntity{})
+					if err != nil {
+						return err
+					}
+					pending, err := pendingMigrations(tx, migrations)
+					if err != nil {
+						return err
+					}
+					for _, m := range pending {
+						l.Infof("Applying migration [%d] %s.", m.version, m.description)
+						err = m.apply(tx)
+						if err != nil {
+							return fmt.Errorf("migration %d: %w", m.version, err)
+						}
+						err = tx.Create(&migrationEntity{Version: m.version, Description: m.description, AppliedAt: time.Now()}).Error
+						if err != nil {
+							return err
+						}
+						applied = append(applied, m)
+					}
+					return nil
+				})
+				if err != nil {
+					return nil, err
+				}
+				return applied, nil
+			}
+		}
+	}
+}
+
+func pendingMigrations(db *gorm.DB, migrations []Migration) ([]Migration, error) {
+	var es []migrationEntity
+	err := db.Find(&es).Error
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[uint32]bool)
+	for _, e := range es {
+		applied[e.Version] = true
+	}
+	pending := make([]Migration, 0)
+	for _, m := range migrations {
+		if !applied[m.version] {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
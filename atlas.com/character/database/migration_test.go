@@ -0,0 +1,67 @@
+package database_test
+
+import (
+	"atlas-character/database"
+	"errors"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	ms := []database.Migration{
+		database.GoMigration(1, "create owned", func(db *gorm.DB) error {
+			return db.AutoMigrate(&owned{})
+		}),
+		database.SQLMigration(2, "seed owned", "INSERT INTO owneds (tenant_id, value) VALUES ('', 1); INSERT INTO owneds (tenant_id, value) VALUES ('', 2);"),
+	}
+
+	pending, err := database.Migrate(l)(db)(ms...)(true)
+	if err != nil || len(pending) != 2 {
+		t.Fatalf("Expected two pending migrations, got: %v %v", pending, err)
+	}
+	if db.Migrator().HasTable(&owned{}) {
+		t.Fatalf("Dry run applied migrations.")
+	}
+
+	applied, err := database.Migrate(l)(db)(ms...)(false)
+	if err != nil || len(applied) != 2 {
+		t.Fatalf("Expected two applied migrations, got: %v %v", applied, err)
+	}
+	var count int64
+	db.Model(&owned{}).Count(&count)
+	if count != 2 {
+		t.Fatalf("Expected two seeded rows, got: %d", count)
+	}
+
+	applied, err = database.Migrate(l)(db)(ms...)(false)
+	if err != nil || len(applied) != 0 {
+		t.Fatalf("Expected migrations to be applied once, got: %v %v", applied, err)
+	}
+
+	failing := append(ms, database.SQLMigration(3, "seed and fail", "INSERT INTO owneds (tenant_id, value) VALUES ('', 3); INSERT INTO missing (value) VALUES (1);"))
+	_, err = database.Migrate(l)(db)(failing...)(false)
+	if err == nil {
+		t.Fatalf("Expected failing migration to fail.")
+	}
+	db.Model(&owned{}).Count(&count)
+	if count != 2 {
+		t.Fatalf("Failed migration was not rolled back, rows: %d", count)
+	}
+	pending, err = database.Migrate(l)(db)(failing...)(true)
+	if err != nil || len(pending) != 1 || pending[0].Version() != 3 {
+		t.Fatalf("Expected failed migration to remain pending, got: %v %v", pending, err)
+	}
+
+	_, err = database.Migrate(l)(db)(ms[1], ms[0])(false)
+	if !errors.Is(err, database.ErrMigrationOrder) {
+		t.Fatalf("Expected out of order migrations to be rejected, got: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	tenantField  = "TenantId"
+	tenantColumn = "tenant_id"
+)
+
+// ErrTenantRequired fails statements on tenant owned tables whose context carries no tenant, unless the context opts out
+// of tenant scoping with WithoutTenant.
+var ErrTenantRequired = errors.New("tenant required for statement on tenant owned table")
+
+type unscopedKey struct{}
+
+// WithoutTenant opts the statements made with the returned context out of tenant scoping, so that they reach the rows of
+// every tenant. It is meant for migrations and maintenance spanning tenants.
+func WithoutTenant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unscopedKey{}, true)
+}
+
+// RegisterTenantCallbacks scopes every statement on a tenant owned table to the tenant carried by the statement's
+// context. Reads, updates and deletes are restricted to the tenant's rows, and created rows are assigned to it. Tables
+// are tenant owned when their entity has a TenantId field. Statements without a tenant in their context fail with
+// ErrTenantRequired, unless the context was made WithoutTenant.
+func RegisterTenantCallbacks(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().Before("gorm:create").Register("tenant:create", assignTenant); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("tenant:query", restrictToTenant); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenant:update", restrictToTenant); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("tenant:delete", restrictToTenant); err != nil {
+		return err
+	}
+	return cb.Row().Before("gorm:row").Register("tenant:row", restrictToTenant)
+}
+
+func restrictToTenant(db *gorm.DB) {
+	id, ok := statementTenant(db)
+	if !ok {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: tenantColumn}, Value: id},
+	}})
+}
+
+func assignTenant(db *gorm.DB) {
+	id, ok := statementTenant(db)
+	if !ok {
+		return
+	}
+	db.Statement.SetColumn(tenantField, id, true)
+}
+
+// statementTenant yields the tenant a statement must be scoped to. It is not ok when the statement need not be scoped,
+// or when it must be but its context carries no tenant, in which case the statement is failed.
+func statementTenant(db *gorm.DB) (uuid.UUID, bool) {
+	if db.Statement.Schema == nil || db.Statement.Schema.LookUpField(tenantField) == nil {
+		return uuid.Nil, false
+	}
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if unscoped, _ := ctx.Value(unscopedKey{}).(bool); unscoped {
+		return uuid.Nil, false
+	}
+	t, err := tenant.FromContext(ctx)()
+	if err != nil {
+		_ = db.AddError(ErrTenantRequired)
+		return uuid.Nil, false
+	}
+	return t.Id(), true
+}
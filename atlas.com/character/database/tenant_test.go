@@ -0,0 +1,112 @@
+package database_test
+
+import (
+	"atlas-character/database"
+	"context"
+	"errors"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"testing"
+)
+
+type owned struct {
+	TenantId uuid.UUID `gorm:"not null"`
+	ID       uint32    `gorm:"primaryKey;autoIncrement;not null"`
+	Value    uint32    `gorm:"not null"`
+}
+
+func testDatabase(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	if err = database.RegisterTenantCallbacks(db); err != nil {
+		t.Fatalf("Failed to register tenant scoping: %v", err)
+	}
+	if err = db.AutoMigrate(&owned{}); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func testContext() (context.Context, uuid.UUID) {
+	te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+	return tenant.WithContext(context.Background(), te), te.Id()
+}
+
+func TestTenantCallbacks(t *testing.T) {
+	db := testDatabase(t)
+	actx, aid := testContext()
+	bctx, _ := testContext()
+
+	// Created rows are assigned to the context's tenant.
+	e := &owned{Value: 1}
+	if err := db.WithContext(actx).Create(e).Error; err != nil {
+		t.Fatalf("Failed to create row: %v", err)
+	}
+	uctx := database.WithoutTenant(context.Background())
+	var stored owned
+	if err := db.WithContext(uctx).First(&stored, e.ID).Error; err != nil {
+		t.Fatalf("Failed to read row: %v", err)
+	}
+	if stored.TenantId != aid {
+		t.Fatalf("Row tenant expected=%s, got=%s", aid, stored.TenantId)
+	}
+
+	// Another tenant can neither see, update nor delete it.
+	err := db.WithContext(bctx).First(&owned{}, e.ID).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("Expected row to be invisible to other tenant, got: %v", err)
+	}
+	res := db.WithContext(bctx).Model(&owned{ID: e.ID}).Update("value", 2)
+	if res.Error != nil || res.RowsAffected != 0 {
+		t.Fatalf("Expected update by other tenant to affect nothing, got: %d (%v)", res.RowsAffected, res.Error)
+	}
+	res = db.WithContext(bctx).Delete(&owned{ID: e.ID})
+	if res.Error != nil || res.RowsAffected != 0 {
+		t.Fatalf("Expected delete by other tenant to affect nothing, got: %d (%v)", res.RowsAffected, res.Error)
+	}
+
+	// The owning tenant can.
+	res = db.WithContext(actx).Model(&owned{ID: e.ID}).Update("value", 3)
+	if res.Error != nil || res.RowsAffected != 1 {
+		t.Fatalf("Expected update by owning tenant, got: %d (%v)", res.RowsAffected, res.Error)
+	}
+	if err = db.WithContext(uctx).First(&stored, e.ID).Error; err != nil || stored.Value != 3 {
+		t.Fatalf("Row value expected=%d, got=%d (%v)", 3, stored.Value, err)
+	}
+}
+
+func TestTenantRequired(t *testing.T) {
+	db := testDatabase(t)
+	actx, _ := testContext()
+
+	e := &owned{Value: 1}
+	if err := db.WithContext(actx).Create(e).Error; err != nil {
+		t.Fatalf("Failed to create row: %v", err)
+	}
+
+	// Statements without a tenant are refused rather than reaching every tenant's rows.
+	if err := db.Create(&owned{Value: 2}).Error; !errors.Is(err, database.ErrTenantRequired) {
+		t.Fatalf("Expected create without a tenant to be refused, got: %v", err)
+	}
+	if err := db.First(&owned{}, e.ID).Error; !errors.Is(err, database.ErrTenantRequired) {
+		t.Fatalf("Expected read without a tenant to be refused, got: %v", err)
+	}
+	if err := db.Model(&owned{ID: e.ID}).Update("value", 3).Error; !errors.Is(err, database.ErrTenantRequired) {
+		t.Fatalf("Expected update without a tenant to be refused, got: %v", err)
+	}
+	if err := db.Delete(&owned{ID: e.ID}).Error; !errors.Is(err, database.ErrTenantRequired) {
+		t.Fatalf("Expected delete without a tenant to be refused, got: %v", err)
+	}
+
+	var stored []owned
+	if err := db.WithContext(database.WithoutTenant(context.Background())).Find(&stored).Error; err != nil {
+		t.Fatalf("Failed to read rows: %v", err)
+	}
+	if len(stored) != 1 || stored[0].Value != 1 {
+		t.Fatalf("Expected only the scoped row unchanged, got: %+v", stored)
+	}
+}
@@ -43,6 +43,186 @@ func (m Model) ReferenceId() uint32 {
 	return m.referenceId
 }
 
+func (m Model) Strength() uint16 {
+	return m.strength
+}
+
+func (m Model) Dexterity() uint16 {
+	return m.dexterity
+}
+
+func (m Model) Intelligence() uint16 {
+	return m.intelligence
+}
+
+func (m Model) Luck() uint16 {
+	return m.luck
+}
+
+func (m Model) HP() uint16 {
+	return m.hp
+}
+
+func (m Model) MP() uint16 {
+	return m.mp
+}
+
+func (m Model) WeaponAttack() uint16 {
+	return m.weaponAttack
+}
+
+func (m Model) MagicAttack() uint16 {
+	return m.magicAttack
+}
+
+func (m Model) WeaponDefense() uint16 {
+	return m.weaponDefense
+}
+
+func (m Model) MagicDefense() uint16 {
+	return m.magicDefense
+}
+
+func (m Model) Accuracy() uint16 {
+	return m.accuracy
+}
+
+func (m Model) Avoidability() uint16 {
+	return m.avoidability
+}
+
+func (m Model) Hands() uint16 {
+	return m.hands
+}
+
+func (m Model) Speed() uint16 {
+	return m.speed
+}
+
+func (m Model) Jump() uint16 {
+	return m.jump
+}
+
+func (m Model) Slots() uint16 {
+	return m.slots
+}
+
 func ReferenceId(m Model) (uint32, error) {
 	return m.ReferenceId(), nil
 }
+
+type modelBuilder struct {
+	m Model
+}
+
+func NewModelBuilder() *modelBuilder {
+	return &modelBuilder{}
+}
+
+func CloneModel(m Model) *modelBuilder {
+	return &modelBuilder{m: m}
+}
+
+func (b *modelBuilder) SetId(id uint32) *modelBuilder {
+	b.m.id = id
+	return b
+}
+
+func (b *modelBuilder) SetItemId(itemId uint32) *modelBuilder {
+	b.m.itemId = itemId
+	return b
+}
+
+func (b *modelBuilder) SetSlot(slot int16) *modelBuilder {
+	b.m.slot = slot
+	return b
+}
+
+func (b *modelBuilder) SetReferenceId(referenceId uint32) *modelBuilder {
+	b.m.referenceId = referenceId
+	return b
+}
+
+func (b *modelBuilder) SetStrength(strength uint16) *modelBuilder {
+	b.m.strength = strength
+	return b
+}
+
+func (b *modelBuilder) SetDexterity(dexterity uint16) *modelBuilder {
+	b.m.dexterity = dexterity
+	return b
+}
+
+func (b *modelBuilder) SetIntelligence(intelligence uint16) *modelBuilder {
+	b.m.intelligence = intelligence
+	return b
+}
+
+func (b *modelBuilder) SetLuck(luck uint16) *modelBuilder {
+	b.m.luck = luck
+	return b
+}
+
+func (b *modelBuilder) SetHP(hp uint16) *modelBuilder {
+	b.m.hp = hp
+	return b
+}
+
+func (b *modelBuilder) SetMP(mp uint16) *modelBuilder {
+	b.m.mp = mp
+	return b
+}
+
+func (b *modelBuilder) SetWeaponAttack(weaponAttack uint16) *modelBuilder {
+	b.m.weaponAttack = weaponAttack
+	return b
+}
+
+func (b *modelBuilder) SetMagicAttack(magicAttack uint16) *modelBuilder {
+	b.m.magicAttack = magicAttack
+	return b
+}
+
+func (b *modelBuilder) SetWeaponDefense(weaponDefense uint16) *modelBuilder {
+	b.m.weaponDefense = weaponDefense
+	return b
+}
+
+func (b *modelBuilder) SetMagicDefense(magicDefense uint16) *modelBuilder {
+	b.m.magicDefense = magicDefense
+	return b
+}
+
+func (b *modelBuilder) SetAccuracy(accuracy uint16) *modelBuilder {
+	b.m.accuracy = accuracy
+	return b
+}
+
+func (b *modelBuilder) SetAvoidability(avoidability uint16) *modelBuilder {
+	b.m.avoidability = avoidability
+	return b
+}
+
+func (b *modelBuilder) SetHands(hands uint16) *modelBuilder {
+	b.m.hands = hands
+	return b
+}
+
+func (b *modelBuilder) SetSpeed(speed uint16) *modelBuilder {
+	b.m.speed = speed
+	return b
+}
+
+func (b *modelBuilder) SetJump(jump uint16) *modelBuilder {
+	b.m.jump = jump
+	return b
+}
+
+func (b *modelBuilder) SetSlots(slots uint16) *modelBuilder {
+	b.m.slots = slots
+	return b
+}
+
+func (b *modelBuilder) Build() Model {
+	return b.m
+}
@@ -5,24 +5,26 @@ import (
 	"atlas-character/equipable/statistics"
 	"atlas-character/slottable"
 	"context"
+	"errors"
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/Chronicle20/atlas-tenant"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
-func byInventoryProvider(db *gorm.DB) func(ctx context.Context) func(inventoryId uint32) model.Provider[[]Model] {
+// ByInventoryProvider provides the equipables held by an inventory, without their generated statistics.
+func ByInventoryProvider(db *gorm.DB) func(ctx context.Context) func(inventoryId uint32) model.Provider[[]Model] {
 	return func(ctx context.Context) func(inventoryId uint32) model.Provider[[]Model] {
 		return func(inventoryId uint32) model.Provider[[]Model] {
 			t := tenant.MustFromContext(ctx)
-			return model.SliceMap[entity, Model](makeModel)(getByInventory(t.Id(), inventoryId)(db))(model.ParallelMap())
+			return model.SliceMap[entity, Model](makeModel)(getByInventory(t.Id(), inventoryId)(db.WithContext(ctx)))(model.ParallelMap())
 		}
 	}
 }
 
 func GetByInventory(l logrus.FieldLogger, db *gorm.DB, ctx context.Context) func(inventoryId uint32) ([]Model, error) {
 	return func(inventoryId uint32) ([]Model, error) {
-		return model.SliceMap(decorateWithStatistics(l, ctx))(byInventoryProvider(db)(ctx)(inventoryId))(model.ParallelMap())()
+		return model.Map(decorateWithStatistics(l, db, ctx))(ByInventoryProvider(db)(ctx)(inventoryId))()
 	}
 }
 
@@ -30,8 +32,21 @@ func EquipmentProvider(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.
 	return func(db *gorm.DB) func(ctx context.Context) func(inventoryId uint32) model.Provider[[]Model] {
 		return func(ctx context.Context) func(inventoryId uint32) model.Provider[[]Model] {
 			return func(inventoryId uint32) model.Provider[[]Model] {
-				fp := model.FilteredProvider[Model](byInventoryProvider(db)(ctx)(inventoryId), model.Filters(FilterOutInventory))
-				return model.SliceMap(decorateWithStatistics(l, ctx))(fp)(model.ParallelMap())
+				fp := model.FilteredProvider[Model](ByInventoryProvider(db)(ctx)(inventoryId), model.Filters(FilterOutInventory))
+				return model.Map(decorateWithStatistics(l, db, ctx))(fp)
+			}
+		}
+	}
+}
+
+// WornProvider provides the equipment worn from an inventory with its generated statistics. Unlike EquipmentProvider, it
+// fails with ErrStatisticsUnavailable should the statistics of any of the equipment not be retrieved.
+func WornProvider(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(inventoryId uint32) model.Provider[[]Model] {
+	return func(db *gorm.DB) func(ctx context.Context) func(inventoryId uint32) model.Provider[[]Model] {
+		return func(ctx context.Context) func(inventoryId uint32) model.Provider[[]Model] {
+			return func(inventoryId uint32) model.Provider[[]Model] {
+				fp := model.FilteredProvider[Model](ByInventoryProvider(db)(ctx)(inventoryId), model.Filters(FilterOutInventory))
+				return model.Map(requireStatistics(l, db, ctx))(fp)
 			}
 		}
 	}
@@ -41,8 +56,8 @@ func InInventoryProvider(l logrus.FieldLogger) func(db *gorm.DB) func(ctx contex
 	return func(db *gorm.DB) func(ctx context.Context) func(inventoryId uint32) model.Provider[[]Model] {
 		return func(ctx context.Context) func(inventoryId uint32) model.Provider[[]Model] {
 			return func(inventoryId uint32) model.Provider[[]Model] {
-				fp := model.FilteredProvider[Model](byInventoryProvider(db)(ctx)(inventoryId), model.Filters(FilterOutEquipment))
-				return model.SliceMap(decorateWithStatistics(l, ctx))(fp)(model.ParallelMap())
+				fp := model.FilteredProvider[Model](ByInventoryProvider(db)(ctx)(inventoryId), model.Filters(FilterOutEquipment))
+				return model.Map(decorateWithStatistics(l, db, ctx))(fp)
 			}
 		}
 	}
@@ -75,7 +90,7 @@ func BySlotProvider(db *gorm.DB) func(ctx context.Context) func(characterId uint
 		return func(characterId uint32) func(slot int16) model.Provider[Model] {
 			return func(slot int16) model.Provider[Model] {
 				t := tenant.MustFromContext(ctx)
-				return model.Map[entity, Model](makeModel)(getBySlot(t.Id(), characterId, slot)(db))
+				return model.Map[entity, Model](makeModel)(getBySlot(t.Id(), characterId, slot)(db.WithContext(ctx)))
 			}
 		}
 	}
@@ -89,6 +104,23 @@ func GetBySlot(db *gorm.DB) func(ctx context.Context) func(characterId uint32, s
 	}
 }
 
+// ReferenceExists reports whether an equipable of the tenant references the statistics identified by referenceId.
+func ReferenceExists(db *gorm.DB) func(ctx context.Context) func(referenceId uint32) (bool, error) {
+	return func(ctx context.Context) func(referenceId uint32) (bool, error) {
+		return func(referenceId uint32) (bool, error) {
+			t := tenant.MustFromContext(ctx)
+			_, err := getByReferenceId(t.Id(), referenceId)(db)()
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+	}
+}
+
 func FilterOutInventory(e Model) bool {
 	return e.Slot() < 0
 }
@@ -97,37 +129,39 @@ func FilterOutEquipment(e Model) bool {
 	return e.Slot() > 0
 }
 
-func CreateItem(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(statCreator statistics.Creator) asset.CharacterAssetCreator {
-	return func(db *gorm.DB) func(ctx context.Context) func(statCreator statistics.Creator) asset.CharacterAssetCreator {
-		return func(ctx context.Context) func(statCreator statistics.Creator) asset.CharacterAssetCreator {
-			return func(statCreator statistics.Creator) asset.CharacterAssetCreator {
-				return func(characterId uint32) asset.InventoryAssetCreator {
-					return func(inventoryId uint32, inventoryType int8) asset.ItemCreator {
-						return func(itemId uint32) asset.Creator {
-							return func(quantity uint32) model.Provider[asset.Asset] {
-								l.Debugf("Creating equipable [%d] for character [%d].", itemId, characterId)
-								slot, err := GetNextFreeSlot(l)(db)(ctx)(inventoryId)()
-								if err != nil {
-									l.WithError(err).Errorf("Unable to locate a free slot to create the item.")
-									return model.ErrorProvider[asset.Asset](err)
-								}
-								l.Debugf("Found open slot [%d] in inventory [%d] of type [%d].", slot, inventoryId, itemId)
-								l.Debugf("Generating new equipable statistics for item [%d].", itemId)
+func CreateItem(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(capacityProvider slottable.CapacityProvider) func(statCreator statistics.Creator) asset.CharacterAssetCreator {
+	return func(db *gorm.DB) func(ctx context.Context) func(capacityProvider slottable.CapacityProvider) func(statCreator statistics.Creator) asset.CharacterAssetCreator {
+		return func(ctx context.Context) func(capacityProvider slottable.CapacityProvider) func(statCreator statistics.Creator) asset.CharacterAssetCreator {
+			return func(capacityProvider slottable.CapacityProvider) func(statCreator statistics.Creator) asset.CharacterAssetCreator {
+				return func(statCreator statistics.Creator) asset.CharacterAssetCreator {
+					return func(characterId uint32) asset.InventoryAssetCreator {
+						return func(inventoryId uint32, inventoryType int8) asset.ItemCreator {
+							return func(itemId uint32) asset.Creator {
+								return func(quantity uint32) model.Provider[asset.Asset] {
+									l.Debugf("Creating equipable [%d] for character [%d].", itemId, characterId)
+									slot, err := GetNextFreeSlot(l)(capacityProvider)(db)(ctx)(inventoryId)()
+									if err != nil {
+										l.WithError(err).Errorf("Unable to locate a free slot to create the item.")
+										return model.ErrorProvider[asset.Asset](err)
+									}
+									l.Debugf("Found open slot [%d] in inventory [%d] of type [%d].", slot, inventoryId, itemId)
+									l.Debugf("Generating new equipable statistics for item [%d].", itemId)
 
-								sm, err := statCreator(itemId)()
-								if err != nil {
-									l.WithError(err).Errorf("Unable to generate equipment [%d] in equipable storage service for character [%d].", itemId, characterId)
-									return model.ErrorProvider[asset.Asset](err)
-								}
+									sm, err := statCreator(itemId)()
+									if err != nil {
+										l.WithError(err).Errorf("Unable to generate equipment [%d] in equipable storage service for character [%d].", itemId, characterId)
+										return model.ErrorProvider[asset.Asset](err)
+									}
 
-								t := tenant.MustFromContext(ctx)
-								i, err := createItem(db, t.Id(), inventoryId, itemId, slot, sm.Id())
-								if err != nil {
-									return model.ErrorProvider[asset.Asset](err)
-								}
+									t := tenant.MustFromContext(ctx)
+									i, err := createItem(db.WithContext(ctx), t.Id(), inventoryId, itemId, slot, sm.Id())
+									if err != nil {
+										return model.ErrorProvider[asset.Asset](err)
+									}
 
-								l.Debugf("Equipable [%d] created for character [%d].", sm.Id(), characterId)
-								return model.Map(ToAsset)(model.Map[Model, Model](model.Decorate[Model](model.Decorators(statisticsDecorator(sm))))(model.FixedProvider[Model](i)))
+									l.Debugf("Equipable [%d] created for character [%d].", sm.Id(), characterId)
+									return model.Map(ToAsset)(model.Map[Model, Model](model.Decorate[Model](model.Decorators(statisticsDecorator(sm))))(model.FixedProvider[Model](i)))
+								}
 							}
 						}
 					}
@@ -137,32 +171,64 @@ func CreateItem(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context
 	}
 }
 
-func GetNextFreeSlot(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(inventoryId uint32) model.Provider[int16] {
-	return func(db *gorm.DB) func(ctx context.Context) func(inventoryId uint32) model.Provider[int16] {
-		return func(ctx context.Context) func(inventoryId uint32) model.Provider[int16] {
-			return func(inventoryId uint32) model.Provider[int16] {
-				ms, err := GetByInventory(l, db, ctx)(inventoryId)
-				if err != nil {
-					return model.ErrorProvider[int16](err)
-				}
-				slot, err := slottable.GetNextFreeSlot(SlottableMapper(model.FixedProvider(ms))(model.ParallelMap()))
-				if err != nil {
-					return model.ErrorProvider[int16](err)
+func GetNextFreeSlot(l logrus.FieldLogger) func(capacityProvider slottable.CapacityProvider) func(db *gorm.DB) func(ctx context.Context) func(inventoryId uint32) model.Provider[int16] {
+	return func(capacityProvider slottable.CapacityProvider) func(db *gorm.DB) func(ctx context.Context) func(inventoryId uint32) model.Provider[int16] {
+		return func(db *gorm.DB) func(ctx context.Context) func(inventoryId uint32) model.Provider[int16] {
+			return func(ctx context.Context) func(inventoryId uint32) model.Provider[int16] {
+				return func(inventoryId uint32) model.Provider[int16] {
+					capacity, err := capacityProvider(db)(ctx)(inventoryId)()
+					if err != nil {
+						return model.ErrorProvider[int16](err)
+					}
+					slot, err := slottable.GetNextFreeSlot(SlottableMapper(ByInventoryProvider(db)(ctx)(inventoryId))(model.ParallelMap()), inventoryId, capacity)
+					if err != nil {
+						return model.ErrorProvider[int16](err)
+					}
+					return model.FixedProvider(slot)
 				}
-				return model.FixedProvider(slot)
 			}
 		}
 	}
 }
 
-func decorateWithStatistics(l logrus.FieldLogger, ctx context.Context) func(e Model) (Model, error) {
-	return func(e Model) (Model, error) {
-		sm, err := statistics.GetById(l, ctx)(e.ReferenceId())
-		if err != nil {
-			l.WithError(err).Errorf("Unable to retrieve generated equipment [%d] statistics.", e.Id())
-			return e, nil
+// ErrStatisticsUnavailable is returned when the generated statistics of an equipable are required but cannot be
+// retrieved.
+var ErrStatisticsUnavailable = errors.New("equipment statistics unavailable")
+
+// decorateWithStatistics decorates equipables with their generated statistics, retrieved in a single batch. Equipables
+// whose statistics cannot be retrieved are left undecorated.
+func decorateWithStatistics(l logrus.FieldLogger, db *gorm.DB, ctx context.Context) func(es []Model) ([]Model, error) {
+	return withStatistics(l, db, ctx, false)
+}
+
+// requireStatistics decorates equipables with their generated statistics, retrieved in a single batch, failing should
+// those of any equipable not be retrieved.
+func requireStatistics(l logrus.FieldLogger, db *gorm.DB, ctx context.Context) func(es []Model) ([]Model, error) {
+	return withStatistics(l, db, ctx, true)
+}
+
+func withStatistics(l logrus.FieldLogger, db *gorm.DB, ctx context.Context, required bool) func(es []Model) ([]Model, error) {
+	return func(es []Model) ([]Model, error) {
+		ids := make([]uint32, 0)
+		for _, e := range es {
+			ids = append(ids, e.ReferenceId())
 		}
-		return statisticsDecorator(sm)(e), nil
+		sms := statistics.GetByIds(l, db, ctx)(ids)
+
+		results := make([]Model, 0)
+		for _, e := range es {
+			sm, ok := sms[e.ReferenceId()]
+			if !ok {
+				l.Errorf("Unable to retrieve generated equipment [%d] statistics.", e.Id())
+				if required {
+					return nil, ErrStatisticsUnavailable
+				}
+				results = append(results, e)
+				continue
+			}
+			results = append(results, statisticsDecorator(sm)(e))
+		}
+		return results, nil
 	}
 }
 
@@ -192,7 +258,7 @@ func UpdateSlot(db *gorm.DB) func(ctx context.Context) func(id uint32, slot int1
 	return func(ctx context.Context) func(id uint32, slot int16) error {
 		return func(id uint32, slot int16) error {
 			t := tenant.MustFromContext(ctx)
-			return updateSlot(db, t.Id(), id, slot)
+			return updateSlot(db.WithContext(ctx), t.Id(), id, slot)
 		}
 	}
 }
@@ -202,12 +268,31 @@ func DeleteByReferenceId(l logrus.FieldLogger) func(db *gorm.DB) func(ctx contex
 		return func(ctx context.Context) model.Operator[uint32] {
 			return func(referenceId uint32) error {
 				l.Debugf("Attempting to delete equipment referencing [%d].", referenceId)
-				err := statistics.Delete(l, ctx)(referenceId)
+				err := statistics.Delete(l, db, ctx)(referenceId)
+				if err != nil {
+					return err
+				}
+				t := tenant.MustFromContext(ctx)
+				return delete(db.WithContext(ctx), t.Id(), referenceId)
+			}
+		}
+	}
+}
+
+// RemoveByReferenceId deletes the equipment referencing the statistics through db, together with the statistics should
+// this service store them. Statistics of the equipable storage service are left to statistics.Release, to be called
+// once the transaction db belongs to commits.
+func RemoveByReferenceId(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) model.Operator[uint32] {
+	return func(db *gorm.DB) func(ctx context.Context) model.Operator[uint32] {
+		return func(ctx context.Context) model.Operator[uint32] {
+			return func(referenceId uint32) error {
+				l.Debugf("Attempting to remove equipment referencing [%d].", referenceId)
+				err := statistics.DeleteLocal(db, ctx)(referenceId)
 				if err != nil {
 					return err
 				}
 				t := tenant.MustFromContext(ctx)
-				return delete(db, t.Id(), referenceId)
+				return delete(db.WithContext(ctx), t.Id(), referenceId)
 			}
 		}
 	}
@@ -219,7 +304,7 @@ func DropByReferenceId(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.
 			return func(referenceId uint32) error {
 				l.Debugf("Attempting to drop equipment referencing [%d].", referenceId)
 				t := tenant.MustFromContext(ctx)
-				return delete(db, t.Id(), referenceId)
+				return delete(db.WithContext(ctx), t.Id(), referenceId)
 			}
 		}
 	}
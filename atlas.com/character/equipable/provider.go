@@ -13,6 +13,12 @@ func getByInventory(tenantId uuid.UUID, inventoryId uint32) database.EntityProvi
 	}
 }
 
+func getByReferenceId(tenantId uuid.UUID, referenceId uint32) database.EntityProvider[entity] {
+	return func(db *gorm.DB) model.Provider[entity] {
+		return database.Query[entity](db, &entity{TenantId: tenantId, ReferenceId: referenceId})
+	}
+}
+
 func getBySlot(tenantId uuid.UUID, characterId uint32, slot int16) database.EntityProvider[entity] {
 	return func(db *gorm.DB) model.Provider[entity] {
 		var results entity
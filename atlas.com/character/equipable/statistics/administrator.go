@@ -0,0 +1,61 @@
+package statistics
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func create(db *gorm.DB, tenantId uuid.UUID, m Model) (Model, error) {
+	e := &entity{
+		TenantId:      tenantId,
+		ItemId:        m.itemId,
+		Strength:      m.strength,
+		Dexterity:     m.dexterity,
+		Intelligence:  m.intelligence,
+		Luck:          m.luck,
+		HP:            m.hp,
+		MP:            m.mp,
+		WeaponAttack:  m.weaponAttack,
+		MagicAttack:   m.magicAttack,
+		WeaponDefense: m.weaponDefense,
+		MagicDefense:  m.magicDefense,
+		Accuracy:      m.accuracy,
+		Avoidability:  m.avoidability,
+		Hands:         m.hands,
+		Speed:         m.speed,
+		Jump:          m.jump,
+		Slots:         m.slots,
+	}
+	err := db.Create(e).Error
+	if err != nil {
+		return Model{}, err
+	}
+	return makeModel(*e)
+}
+
+func makeModel(e entity) (Model, error) {
+	return Model{
+		id:            e.ID,
+		itemId:        e.ItemId,
+		strength:      e.Strength,
+		dexterity:     e.Dexterity,
+		intelligence:  e.Intelligence,
+		luck:          e.Luck,
+		hp:            e.HP,
+		mp:            e.MP,
+		weaponAttack:  e.WeaponAttack,
+		magicAttack:   e.MagicAttack,
+		weaponDefense: e.WeaponDefense,
+		magicDefense:  e.MagicDefense,
+		accuracy:      e.Accuracy,
+		avoidability:  e.Avoidability,
+		hands:         e.Hands,
+		speed:         e.Speed,
+		jump:          e.Jump,
+		slots:         e.Slots,
+	}, nil
+}
+
+func deleteEntity(db *gorm.DB, tenantId uuid.UUID, id uint32) error {
+	return db.Where(&entity{TenantId: tenantId, ID: id}).Delete(&entity{}).Error
+}
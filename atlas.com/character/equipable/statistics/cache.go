@@ -0,0 +1,90 @@
+package statistics
+
+import (
+	"github.com/google/uuid"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultCacheTTL is how long retrieved statistics are served from the cache when EQUIPABLE_STATISTICS_CACHE_TTL is not
+// set. Statistics changed by other services, such as by scrolling, may be served stale for up to this long.
+const defaultCacheTTL = 5 * time.Minute
+
+type cacheEntry struct {
+	model   Model
+	expires time.Time
+}
+
+type cache struct {
+	ttl     time.Duration
+	mutex   sync.RWMutex
+	tenants map[uuid.UUID]map[uint32]cacheEntry
+	pruned  time.Time
+	now     func() time.Time
+}
+
+var c *cache
+var once sync.Once
+
+// GetCache yields the read-through cache of generated equipment statistics, kept separately for every tenant.
+func GetCache() *cache {
+	once.Do(func() {
+		ttl := defaultCacheTTL
+		if val, ok := os.LookupEnv("EQUIPABLE_STATISTICS_CACHE_TTL"); ok {
+			if d, err := time.ParseDuration(val); err == nil {
+				ttl = d
+			}
+		}
+		c = &cache{ttl: ttl, tenants: make(map[uuid.UUID]map[uint32]cacheEntry), now: time.Now}
+	})
+	return c
+}
+
+// Get yields the unexpired statistics of the tenant's equipment, if cached.
+func (c *cache) Get(tenantId uuid.UUID, equipmentId uint32) (Model, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	e, ok := c.tenants[tenantId][equipmentId]
+	if !ok || !c.now().Before(e.expires) {
+		return Model{}, false
+	}
+	return e.model, true
+}
+
+func (c *cache) Put(tenantId uuid.UUID, m Model) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.now().Sub(c.pruned) > c.ttl {
+		c.prune()
+	}
+	if _, ok := c.tenants[tenantId]; !ok {
+		c.tenants[tenantId] = make(map[uint32]cacheEntry)
+	}
+	c.tenants[tenantId][m.Id()] = cacheEntry{model: m, expires: c.now().Add(c.ttl)}
+}
+
+func (c *cache) Invalidate(tenantId uuid.UUID, equipmentId uint32) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.tenants[tenantId], equipmentId)
+}
+
+// prune removes the expired statistics of every tenant. The caller must hold the write lock.
+func (c *cache) prune() {
+	now := c.now()
+	c.pruned = now
+	for tenantId, es := range c.tenants {
+		for id, e := range es {
+			if !now.Before(e.expires) {
+				delete(es, id)
+			}
+		}
+		if len(es) == 0 {
+			delete(c.tenants, tenantId)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package statistics
+
+import (
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Now()
+	c := &cache{ttl: time.Minute, tenants: make(map[uuid.UUID]map[uint32]cacheEntry), now: func() time.Time { return now }}
+	a := uuid.New()
+	b := uuid.New()
+
+	c.Put(a, Model{id: 1, strength: 5})
+	if m, ok := c.Get(a, 1); !ok || m.Strength() != 5 {
+		t.Fatalf("Expected cached statistics, got: %v %v", m, ok)
+	}
+	if _, ok := c.Get(b, 1); ok {
+		t.Fatalf("Statistics leaked across tenants.")
+	}
+
+	c.Invalidate(a, 1)
+	if _, ok := c.Get(a, 1); ok {
+		t.Fatalf("Invalidated statistics still cached.")
+	}
+
+	c.Put(a, Model{id: 2})
+	now = now.Add(2 * time.Minute)
+	if _, ok := c.Get(a, 2); ok {
+		t.Fatalf("Expired statistics still cached.")
+	}
+	c.Put(b, Model{id: 3})
+	if _, ok := c.tenants[a]; ok {
+		t.Fatalf("Expired statistics not pruned.")
+	}
+}
@@ -0,0 +1,38 @@
+package statistics
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func Migration(db *gorm.DB) error {
+	return db.AutoMigrate(&entity{})
+}
+
+// entity holds statistics generated by this service, when they are stored locally rather than by the equipable storage
+// service.
+type entity struct {
+	TenantId      uuid.UUID `gorm:"not null"`
+	ID            uint32    `gorm:"primaryKey;autoIncrement;not null"`
+	ItemId        uint32    `gorm:"not null"`
+	Strength      uint16    `gorm:"not null;default=0"`
+	Dexterity     uint16    `gorm:"not null;default=0"`
+	Intelligence  uint16    `gorm:"not null;default=0"`
+	Luck          uint16    `gorm:"not null;default=0"`
+	HP            uint16    `gorm:"not null;default=0"`
+	MP            uint16    `gorm:"not null;default=0"`
+	WeaponAttack  uint16    `gorm:"not null;default=0"`
+	MagicAttack   uint16    `gorm:"not null;default=0"`
+	WeaponDefense uint16    `gorm:"not null;default=0"`
+	MagicDefense  uint16    `gorm:"not null;default=0"`
+	Accuracy      uint16    `gorm:"not null;default=0"`
+	Avoidability  uint16    `gorm:"not null;default=0"`
+	Hands         uint16    `gorm:"not null;default=0"`
+	Speed         uint16    `gorm:"not null;default=0"`
+	Jump          uint16    `gorm:"not null;default=0"`
+	Slots         uint16    `gorm:"not null;default=0"`
+}
+
+func (e entity) TableName() string {
+	return "equipable_statistics"
+}
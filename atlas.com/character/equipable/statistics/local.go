@@ -0,0 +1,101 @@
+package statistics
+
+import (
+	statistics2 "atlas-character/equipment/statistics"
+	"context"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-tenant"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"math/rand"
+	"os"
+)
+
+const (
+	StoreRemote = "remote"
+	StoreLocal  = "local"
+)
+
+// maxVariance bounds the standard variance of a generated statistic, which is otherwise a tenth of its base value.
+const maxVariance = 5
+
+// isLocal reports whether statistics are generated and stored by this service, as selected by
+// EQUIPABLE_STATISTICS_STORE, rather than by the equipable storage service.
+func isLocal() bool {
+	return os.Getenv("EQUIPABLE_STATISTICS_STORE") == StoreLocal
+}
+
+// createLocal generates statistics for the item from its game data base statistics, and stores them.
+func createLocal(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) Creator {
+	return func(db *gorm.DB) func(ctx context.Context) Creator {
+		return func(ctx context.Context) Creator {
+			return func(itemId uint32) model.Provider[Model] {
+				base, err := statistics2.GetById(l, ctx)(itemId)
+				if err != nil {
+					l.WithError(err).Errorf("Unable to retrieve base statistics of equipment [%d].", itemId)
+					return model.ErrorProvider[Model](err)
+				}
+				t := tenant.MustFromContext(ctx)
+				m, err := create(db.WithContext(ctx), t.Id(), generate(itemId, base))
+				if err != nil {
+					return model.ErrorProvider[Model](err)
+				}
+				return model.FixedProvider(m)
+			}
+		}
+	}
+}
+
+// generate applies the standard variance to every base statistic of an item, except for hands and upgrade slots.
+func generate(itemId uint32, base statistics2.Model) Model {
+	return Model{
+		itemId:        itemId,
+		strength:      vary(base.Strength()),
+		dexterity:     vary(base.Dexterity()),
+		intelligence:  vary(base.Intelligence()),
+		luck:          vary(base.Luck()),
+		hp:            vary(base.HP()),
+		mp:            vary(base.MP()),
+		weaponAttack:  vary(base.WeaponAttack()),
+		magicAttack:   vary(base.MagicAttack()),
+		weaponDefense: vary(base.WeaponDefense()),
+		magicDefense:  vary(base.MagicDefense()),
+		accuracy:      vary(base.Accuracy()),
+		avoidability:  vary(base.Avoidability()),
+		hands:         base.Hands(),
+		speed:         vary(base.Speed()),
+		jump:          vary(base.Jump()),
+		slots:         base.Slots(),
+	}
+}
+
+// vary yields a value drawn uniformly within a tenth of value, rounded up and at most maxVariance, either side of it.
+// Statistics an item does not have remain absent.
+func vary(value uint16) uint16 {
+	if value == 0 {
+		return 0
+	}
+	r := min((int(value)+9)/10, maxVariance)
+	return uint16(int(value) - r + rand.Intn(2*r+1))
+}
+
+func byIdLocal(db *gorm.DB, ctx context.Context) func(equipmentId uint32) model.Provider[Model] {
+	return func(equipmentId uint32) model.Provider[Model] {
+		t := tenant.MustFromContext(ctx)
+		return model.Map(makeModel)(getById(t.Id(), equipmentId)(db.WithContext(ctx)))
+	}
+}
+
+func byIdsLocal(db *gorm.DB, ctx context.Context) func(equipmentIds []uint32) ([]Model, error) {
+	return func(equipmentIds []uint32) ([]Model, error) {
+		t := tenant.MustFromContext(ctx)
+		return model.SliceMap[entity, Model](makeModel)(getByIds(t.Id(), equipmentIds)(db.WithContext(ctx)))()()
+	}
+}
+
+func deleteLocal(db *gorm.DB, ctx context.Context) func(equipmentId uint32) error {
+	return func(equipmentId uint32) error {
+		t := tenant.MustFromContext(ctx)
+		return deleteEntity(db.WithContext(ctx), t.Id(), equipmentId)
+	}
+}
@@ -0,0 +1,81 @@
+package statistics
+
+import (
+	"atlas-character/database"
+	"context"
+	"errors"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestVary(t *testing.T) {
+	for _, c := range []struct {
+		value    uint16
+		variance uint16
+	}{{0, 0}, {1, 1}, {10, 1}, {11, 2}, {40, 4}, {120, 5}} {
+		seen := make(map[uint16]bool)
+		for i := 0; i < 1000; i++ {
+			v := vary(c.value)
+			if v+c.variance < c.value || v > c.value+c.variance {
+				t.Fatalf("Value [%d] varied beyond [%d], got: %d", c.value, c.variance, v)
+			}
+			seen[v] = true
+		}
+		if len(seen) != int(2*c.variance+1) {
+			t.Fatalf("Expected every value within [%d] of [%d], got: %v", c.variance, c.value, seen)
+		}
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	t.Setenv("EQUIPABLE_STATISTICS_STORE", StoreLocal)
+	l, _ := test.NewNullLogger()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	if err = database.RegisterTenantCallbacks(db); err != nil {
+		t.Fatalf("Failed to register tenant scoping: %v", err)
+	}
+	if err = Migration(db); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	at, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+	actx := tenant.WithContext(context.Background(), at)
+	bt, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+	bctx := tenant.WithContext(context.Background(), bt)
+
+	a, err := create(db.WithContext(actx), at.Id(), Model{itemId: 1302000, weaponAttack: 17, slots: 7})
+	if err != nil {
+		t.Fatalf("Failed to create statistics: %v", err)
+	}
+	b, err := create(db.WithContext(actx), at.Id(), Model{itemId: 1040002, weaponDefense: 3, slots: 7})
+	if err != nil {
+		t.Fatalf("Failed to create statistics: %v", err)
+	}
+
+	m, err := Existing(l)(actx)(a.Id())(db)(1302000)()
+	if err != nil || m.WeaponAttack() != 17 || m.Slots() != 7 {
+		t.Fatalf("Expected existing statistics, got: %v %v", m, err)
+	}
+	if _, err = GetById(l, db.WithContext(bctx), bctx)(a.Id()); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("Statistics leaked across tenants, got: %v", err)
+	}
+
+	ms := GetByIds(l, db.WithContext(actx), actx)([]uint32{a.Id(), b.Id(), b.Id() + 1})
+	if len(ms) != 2 || ms[b.Id()].WeaponDefense() != 3 {
+		t.Fatalf("Expected statistics of both equipment, got: %v", ms)
+	}
+
+	if err = Delete(l, db.WithContext(actx), actx)(a.Id()); err != nil {
+		t.Fatalf("Failed to delete statistics: %v", err)
+	}
+	if _, err = GetById(l, db.WithContext(actx), actx)(a.Id()); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("Expected deleted statistics to be absent, got: %v", err)
+	}
+}
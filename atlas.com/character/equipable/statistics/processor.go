@@ -4,39 +4,203 @@ import (
 	"context"
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/Chronicle20/atlas-rest/requests"
+	"github.com/Chronicle20/atlas-tenant"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 type Creator func(itemId uint32) model.Provider[Model]
 
-func Create(l logrus.FieldLogger) func(ctx context.Context) Creator {
-	return func(ctx context.Context) Creator {
+// CreatorProvider binds a Creator to the database of the transaction creating the equipable, so that locally stored
+// statistics are created and read within it.
+type CreatorProvider func(db *gorm.DB) Creator
+
+// Create generates new statistics for an item, either by the equipable storage service or, when
+// EQUIPABLE_STATISTICS_STORE is local, by this service.
+func Create(l logrus.FieldLogger) func(ctx context.Context) CreatorProvider {
+	return func(ctx context.Context) CreatorProvider {
+		return func(db *gorm.DB) Creator {
+			if isLocal() {
+				return createLocal(l)(db)(ctx)
+			}
+			return func(itemId uint32) model.Provider[Model] {
+				ro, err := requestCreate(itemId)(l, ctx)
+				if err != nil {
+					l.WithError(err).Errorf("Generating equipment item %d, they were not awarded this item. Check request in ESO service.", itemId)
+					return model.ErrorProvider[Model](err)
+				}
+				m, err := makeEquipment(ro)
+				if err != nil {
+					return model.ErrorProvider[Model](err)
+				}
+				GetCache().Put(tenant.MustFromContext(ctx).Id(), m)
+				return model.FixedProvider(m)
+			}
+		}
+	}
+}
+
+// Tracked records the ids of the statistics the creators of cp generate, so that they may be discarded should the
+// transaction creating the equipment roll back.
+func Tracked(cp CreatorProvider, ids *[]uint32) CreatorProvider {
+	return func(db *gorm.DB) Creator {
+		c := cp(db)
 		return func(itemId uint32) model.Provider[Model] {
-			ro, err := requestCreate(itemId)(l, ctx)
+			m, err := c(itemId)()
 			if err != nil {
-				l.WithError(err).Errorf("Generating equipment item %d, they were not awarded this item. Check request in ESO service.", itemId)
 				return model.ErrorProvider[Model](err)
 			}
-			return model.Map(makeEquipment)(model.FixedProvider(ro))
+			*ids = append(*ids, m.Id())
+			return model.FixedProvider(m)
+		}
+	}
+}
+
+// Discard deletes statistics generated for equipment which was never created. Locally stored statistics are rolled back
+// with the transaction which created them, so only those of the equipable storage service are deleted.
+func Discard(l logrus.FieldLogger, ctx context.Context) func(equipmentIds []uint32) {
+	return func(equipmentIds []uint32) {
+		if isLocal() {
+			return
+		}
+		for _, id := range equipmentIds {
+			err := deleteById(id)(l, ctx)
+			if err != nil {
+				l.WithError(err).Errorf("Unable to discard generated equipment [%d] statistics.", id)
+				continue
+			}
+			GetCache().Invalidate(tenant.MustFromContext(ctx).Id(), id)
+		}
+	}
+}
+
+// Existing provides the already generated statistics identified by equipmentId rather than generating new ones.
+func Existing(l logrus.FieldLogger) func(ctx context.Context) func(equipmentId uint32) CreatorProvider {
+	return func(ctx context.Context) func(equipmentId uint32) CreatorProvider {
+		return func(equipmentId uint32) CreatorProvider {
+			return func(db *gorm.DB) Creator {
+				return func(itemId uint32) model.Provider[Model] {
+					return byEquipmentIdModelProvider(l, db, ctx)(equipmentId)
+				}
+			}
 		}
 	}
 }
 
-func byEquipmentIdModelProvider(l logrus.FieldLogger, ctx context.Context) func(equipmentId uint32) model.Provider[Model] {
+// byEquipmentIdModelProvider provides cached statistics, retrieving and caching them on a miss. Locally stored
+// statistics are read from the database instead.
+func byEquipmentIdModelProvider(l logrus.FieldLogger, db *gorm.DB, ctx context.Context) func(equipmentId uint32) model.Provider[Model] {
 	return func(equipmentId uint32) model.Provider[Model] {
-		return requests.Provider[RestModel, Model](l, ctx)(requestById(equipmentId), makeEquipment)
+		if isLocal() {
+			return byIdLocal(db, ctx)(equipmentId)
+		}
+		t := tenant.MustFromContext(ctx)
+		if m, ok := GetCache().Get(t.Id(), equipmentId); ok {
+			return model.FixedProvider(m)
+		}
+		m, err := requests.Provider[RestModel, Model](l, ctx)(requestById(equipmentId), makeEquipment)()
+		if err != nil {
+			return model.ErrorProvider[Model](err)
+		}
+		GetCache().Put(t.Id(), m)
+		return model.FixedProvider(m)
 	}
 }
 
-func GetById(l logrus.FieldLogger, ctx context.Context) func(equipmentId uint32) (Model, error) {
+func GetById(l logrus.FieldLogger, db *gorm.DB, ctx context.Context) func(equipmentId uint32) (Model, error) {
 	return func(equipmentId uint32) (Model, error) {
-		return byEquipmentIdModelProvider(l, ctx)(equipmentId)()
+		return byEquipmentIdModelProvider(l, db, ctx)(equipmentId)()
+	}
+}
+
+// GetByIds retrieves the statistics of several equipment, keyed by equipment id. Cached statistics are served from the
+// cache, and the rest are retrieved with a single request. Should the batch request fail, they are retrieved one by one.
+// Equipment whose statistics cannot be retrieved is absent from the result. Locally stored statistics are read from the
+// database in a single query.
+func GetByIds(l logrus.FieldLogger, db *gorm.DB, ctx context.Context) func(equipmentIds []uint32) map[uint32]Model {
+	return func(equipmentIds []uint32) map[uint32]Model {
+		t := tenant.MustFromContext(ctx)
+		results := make(map[uint32]Model)
+		if isLocal() {
+			ms, err := byIdsLocal(db, ctx)(equipmentIds)
+			if err != nil {
+				l.WithError(err).Errorf("Unable to retrieve [%d] equipment statistics.", len(equipmentIds))
+			}
+			for _, m := range ms {
+				results[m.Id()] = m
+			}
+			return results
+		}
+		missed := make(map[uint32]bool)
+		misses := make([]uint32, 0)
+		for _, id := range equipmentIds {
+			if m, ok := GetCache().Get(t.Id(), id); ok {
+				results[id] = m
+			} else if !missed[id] {
+				missed[id] = true
+				misses = append(misses, id)
+			}
+		}
+		if len(misses) == 0 {
+			return results
+		}
+
+		ms, err := requests.SliceProvider[RestModel, Model](l, ctx)(requestByIds(misses), makeEquipment, model.Filters[Model]())()
+		if err != nil {
+			l.WithError(err).Warnf("Unable to retrieve [%d] equipment statistics in a batch, retrieving them individually.", len(misses))
+			for _, id := range misses {
+				m, err := GetById(l, db, ctx)(id)
+				if err != nil {
+					l.WithError(err).Errorf("Unable to retrieve generated equipment [%d] statistics.", id)
+					continue
+				}
+				results[id] = m
+			}
+			return results
+		}
+		for _, m := range ms {
+			GetCache().Put(t.Id(), m)
+			results[m.Id()] = m
+		}
+		return results
 	}
 }
 
-func Delete(l logrus.FieldLogger, ctx context.Context) func(equipmentId uint32) error {
+func Delete(l logrus.FieldLogger, db *gorm.DB, ctx context.Context) func(equipmentId uint32) error {
 	return func(equipmentId uint32) error {
-		return deleteById(equipmentId)(l, ctx)
+		err := DeleteLocal(db, ctx)(equipmentId)
+		if err != nil {
+			return err
+		}
+		return Release(l, ctx)(equipmentId)
+	}
+}
+
+// DeleteLocal deletes statistics stored by this service through db, which is expected to be the transaction deleting
+// the equipment referencing them. Statistics of the equipable storage service are left to Release.
+func DeleteLocal(db *gorm.DB, ctx context.Context) func(equipmentId uint32) error {
+	return func(equipmentId uint32) error {
+		if !isLocal() {
+			return nil
+		}
+		return deleteLocal(db, ctx)(equipmentId)
+	}
+}
+
+// Release deletes statistics from the equipable storage service, which cannot take part in a transaction, so is to be
+// called once the deletion of the equipment referencing them commits. Locally stored statistics are left to
+// DeleteLocal.
+func Release(l logrus.FieldLogger, ctx context.Context) func(equipmentId uint32) error {
+	return func(equipmentId uint32) error {
+		if isLocal() {
+			return nil
+		}
+		err := deleteById(equipmentId)(l, ctx)
+		if err != nil {
+			return err
+		}
+		GetCache().Invalidate(tenant.MustFromContext(ctx).Id(), equipmentId)
+		return nil
 	}
 }
 
@@ -0,0 +1,25 @@
+package statistics
+
+import (
+	"atlas-character/database"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func getById(tenantId uuid.UUID, id uint32) database.EntityProvider[entity] {
+	return func(db *gorm.DB) model.Provider[entity] {
+		return database.Query[entity](db, &entity{TenantId: tenantId, ID: id})
+	}
+}
+
+func getByIds(tenantId uuid.UUID, ids []uint32) database.EntityProvider[[]entity] {
+	return func(db *gorm.DB) model.Provider[[]entity] {
+		var results []entity
+		err := db.Where("tenant_id = ? AND id IN ?", tenantId, ids).Find(&results).Error
+		if err != nil {
+			return model.ErrorProvider[[]entity](err)
+		}
+		return model.FixedProvider(results)
+	}
+}
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"github.com/Chronicle20/atlas-rest/requests"
 	"os"
+	"strconv"
+	"strings"
 )
 
 const (
 	equipmentResource = "equipment"
 	equipResource     = equipmentResource + "/%d"
+	equipsResource    = equipmentResource + "?ids=%s"
 )
 
 func getBaseRequest() string {
@@ -27,6 +30,14 @@ func requestById(equipmentId uint32) requests.Request[RestModel] {
 	return rest.MakeGetRequest[RestModel](fmt.Sprintf(getBaseRequest()+equipResource, equipmentId))
 }
 
+func requestByIds(equipmentIds []uint32) requests.Request[[]RestModel] {
+	ids := make([]string, 0)
+	for _, id := range equipmentIds {
+		ids = append(ids, strconv.Itoa(int(id)))
+	}
+	return rest.MakeGetRequest[[]RestModel](fmt.Sprintf(getBaseRequest()+equipsResource, strings.Join(ids, ",")))
+}
+
 func deleteById(equipmentId uint32) requests.EmptyBodyRequest {
 	return rest.MakeDeleteRequest(fmt.Sprintf(getBaseRequest()+equipResource, equipmentId))
 }
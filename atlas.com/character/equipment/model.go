@@ -52,6 +52,15 @@ func NewModel() Model {
 	return m
 }
 
+// Slots returns every equipment slot.
+func (m Model) Slots() []slot.Model {
+	return []slot.Model{m.hat, m.medal, m.forehead, m.ring1, m.ring2, m.eye, m.earring, m.shoulder, m.cape, m.top, m.pendant, m.weapon, m.shield, m.gloves, m.bottom, m.belt, m.ring3, m.ring4, m.shoes}
+}
+
+func (m Model) Weapon() slot.Model {
+	return m.weapon
+}
+
 type SlotSetter func(model *equipable.Model) Model
 
 func (m Model) SetHat(e *equipable.Model) Model {
@@ -5,10 +5,13 @@ import (
 	"atlas-character/equipment/slot"
 	"atlas-character/equipment/slot/information"
 	"atlas-character/equipment/statistics"
+	"atlas-character/inventory/item"
 	"context"
+	"errors"
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"sort"
 )
 
 func Delete(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(m Model) error {
@@ -42,39 +45,144 @@ func deleteBySlot(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Conte
 	}
 }
 
-type DestinationProvider func(itemId uint32) model.Provider[int16]
+// SlotPredicate reports a property of an equipment slot, such as whether it is occupied or available.
+type SlotPredicate func(slot int16) bool
+
+// AllSlotsAvailable makes every equipment slot available.
+func AllSlotsAvailable(_ int16) bool {
+	return true
+}
+
+// LockedSlots makes every equipment slot available except those given, and their cash counterparts.
+func LockedSlots(locked ...int16) SlotPredicate {
+	return func(s int16) bool {
+		for _, ls := range locked {
+			if s == ls || s == ls-100 {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AvailableSlotsProvider supplies the equipment slots available in the tenant's version. Availability is the same for
+// every character of a tenant, as slots unlocked by a character, such as ring slots unlocked by completing a quest, are
+// not tracked by this service.
+type AvailableSlotsProvider func(l logrus.FieldLogger) func(ctx context.Context) model.Provider[SlotPredicate]
+
+var ErrNoAvailableSlot = errors.New("no available equipment slot")
+
+// DestinationProvider resolves the equipment slot an item is to be equipped in, given which slots are occupied.
+type DestinationProvider func(itemId uint32, occupied SlotPredicate) model.Provider[int16]
 
 func FixedDestinationProvider(destination int16) DestinationProvider {
-	return func(itemId uint32) model.Provider[int16] {
+	return func(itemId uint32, occupied SlotPredicate) model.Provider[int16] {
 		return func() (int16, error) {
 			return destination, nil
 		}
 	}
 }
 
-func GetEquipmentDestination(l logrus.FieldLogger) func(ctx context.Context) DestinationProvider {
-	return func(ctx context.Context) DestinationProvider {
-		return func(itemId uint32) model.Provider[int16] {
-			slots, err := information.GetById(l, ctx)(itemId)
-			if err != nil {
-				l.WithError(err).Errorf("Unable to retrieve destination slots for item [%d].", itemId)
-				return model.ErrorProvider[int16](err)
-			} else if len(slots) <= 0 {
-				l.Errorf("Unable to retrieve destination slots for item [%d].", itemId)
-				return model.ErrorProvider[int16](err)
+func GetEquipmentDestination(l logrus.FieldLogger) func(ctx context.Context) func(available SlotPredicate) DestinationProvider {
+	return func(ctx context.Context) func(available SlotPredicate) DestinationProvider {
+		return func(available SlotPredicate) DestinationProvider {
+			return func(itemId uint32, occupied SlotPredicate) model.Provider[int16] {
+				slots, err := information.GetById(l, ctx)(itemId)
+				if err != nil {
+					l.WithError(err).Errorf("Unable to retrieve destination slots for item [%d].", itemId)
+					return model.ErrorProvider[int16](err)
+				} else if len(slots) <= 0 {
+					l.Errorf("Unable to retrieve destination slots for item [%d].", itemId)
+					return model.ErrorProvider[int16](ErrNoAvailableSlot)
+				}
+				is, err := statistics.GetById(l, ctx)(itemId)
+				if err != nil {
+					return model.ErrorProvider[int16](err)
+				}
+
+				candidates := make([]int16, 0)
+				for _, s := range slots {
+					if is.Cash() {
+						candidates = append(candidates, s.Slot()-100)
+					} else {
+						candidates = append(candidates, s.Slot())
+					}
+				}
+				destination, err := SelectDestination(candidates, available, occupied)
+				if err != nil {
+					l.WithError(err).Errorf("Unable to select destination slot for item [%d].", itemId)
+					return model.ErrorProvider[int16](err)
+				}
+				return model.FixedProvider(destination)
+			}
+		}
+	}
+}
+
+// replacementOrder is the order in which interchangeable slots are filled, and in which they are replaced once all
+// are occupied. Slots not listed follow in the order given by game data.
+var replacementOrder = []slot.Position{slot.PositionRing1, slot.PositionRing2, slot.PositionRing3, slot.PositionRing4}
+
+// SelectDestination chooses from candidate slots an available one, preferring slots which are not occupied. When all
+// available candidates are occupied, the first in replacement order is chosen.
+func SelectDestination(candidates []int16, available SlotPredicate, occupied SlotPredicate) (int16, error) {
+	ordered := make([]int16, 0)
+	for _, c := range candidates {
+		if available(c) {
+			ordered = append(ordered, c)
+		}
+	}
+	if len(ordered) == 0 {
+		return 0, ErrNoAvailableSlot
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return replacementRank(ordered[i]) < replacementRank(ordered[j])
+	})
+	for _, c := range ordered {
+		if !occupied(c) {
+			return c, nil
+		}
+	}
+	return ordered[0], nil
+}
+
+func replacementRank(s int16) int {
+	p := s
+	if p <= -100 {
+		p += 100
+	}
+	for i, o := range replacementOrder {
+		if slot.Position(p) == o {
+			return i
+		}
+	}
+	return len(replacementOrder)
+}
+
+// TwoHandedProvider determines whether an item is a weapon which prevents a shield from being worn.
+type TwoHandedProvider func(itemId uint32) model.Provider[bool]
+
+// WeaponTypeTwoHandedProvider determines two-handedness from the weapon type encoded in the item id alone.
+func WeaponTypeTwoHandedProvider(itemId uint32) model.Provider[bool] {
+	return model.FixedProvider(item.IsTwoHanded(itemId))
+}
+
+// GetTwoHandedProvider determines two-handedness from the weapon type, or the two-handed flag given by game data.
+func GetTwoHandedProvider(l logrus.FieldLogger) func(ctx context.Context) TwoHandedProvider {
+	return func(ctx context.Context) TwoHandedProvider {
+		return func(itemId uint32) model.Provider[bool] {
+			if item.GetWeaponType(itemId) == item.WeaponTypeInvalid {
+				return model.FixedProvider(false)
+			}
+			if item.IsTwoHanded(itemId) {
+				return model.FixedProvider(true)
 			}
 			is, err := statistics.GetById(l, ctx)(itemId)
 			if err != nil {
-				return model.ErrorProvider[int16](err)
-			}
-
-			destination := int16(0)
-			if is.Cash() {
-				destination = slots[0].Slot() - 100
-			} else {
-				destination = slots[0].Slot()
+				l.WithError(err).Errorf("Unable to retrieve statistics for weapon [%d].", itemId)
+				return model.ErrorProvider[bool](err)
 			}
-			return model.FixedProvider(destination)
+			return model.FixedProvider(is.TwoHanded())
 		}
 	}
 }
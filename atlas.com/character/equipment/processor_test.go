@@ -0,0 +1,69 @@
+package equipment_test
+
+import (
+	"atlas-character/equipment"
+	"atlas-character/equipment/slot"
+	"errors"
+	"testing"
+)
+
+var rings = []int16{int16(slot.PositionRing4), int16(slot.PositionRing2), int16(slot.PositionRing3), int16(slot.PositionRing1)}
+
+func occupiedSlots(occupied ...slot.Position) equipment.SlotPredicate {
+	return func(s int16) bool {
+		for _, o := range occupied {
+			if s == int16(o) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestSelectDestinationPrefersEmpty(t *testing.T) {
+	for _, tc := range []struct {
+		occupied []slot.Position
+		expected slot.Position
+	}{
+		{[]slot.Position{}, slot.PositionRing1},
+		{[]slot.Position{slot.PositionRing1}, slot.PositionRing2},
+		{[]slot.Position{slot.PositionRing1, slot.PositionRing2}, slot.PositionRing3},
+		{[]slot.Position{slot.PositionRing1, slot.PositionRing3}, slot.PositionRing2},
+		{[]slot.Position{slot.PositionRing1, slot.PositionRing2, slot.PositionRing3}, slot.PositionRing4},
+		{[]slot.Position{slot.PositionRing1, slot.PositionRing2, slot.PositionRing3, slot.PositionRing4}, slot.PositionRing1},
+	} {
+		d, err := equipment.SelectDestination(rings, equipment.AllSlotsAvailable, occupiedSlots(tc.occupied...))
+		if err != nil {
+			t.Fatalf("Failed to select destination: %v", err)
+		}
+		if d != int16(tc.expected) {
+			t.Fatalf("Expected destination [%d] with %v occupied, was [%d].", tc.expected, tc.occupied, d)
+		}
+	}
+}
+
+func TestSelectDestinationLockedSlots(t *testing.T) {
+	available := equipment.LockedSlots(int16(slot.PositionRing3), int16(slot.PositionRing4))
+	d, err := equipment.SelectDestination(rings, available, occupiedSlots(slot.PositionRing1, slot.PositionRing2))
+	if err != nil {
+		t.Fatalf("Failed to select destination: %v", err)
+	}
+	if d != int16(slot.PositionRing1) {
+		t.Fatalf("Expected locked ring slots to be skipped and ring 1 replaced, was [%d].", d)
+	}
+
+	_, err = equipment.SelectDestination([]int16{int16(slot.PositionRing3)}, available, occupiedSlots())
+	if !errors.Is(err, equipment.ErrNoAvailableSlot) {
+		t.Fatalf("Expected no available slot, got: %v", err)
+	}
+}
+
+func TestSelectDestinationSingleSlot(t *testing.T) {
+	d, err := equipment.SelectDestination([]int16{int16(slot.PositionHat) - 100}, equipment.AllSlotsAvailable, occupiedSlots(slot.PositionHat-100))
+	if err != nil {
+		t.Fatalf("Failed to select destination: %v", err)
+	}
+	if d != int16(slot.PositionHat)-100 {
+		t.Fatalf("Expected cash hat slot, was [%d].", d)
+	}
+}
@@ -0,0 +1,181 @@
+package equipment
+
+import (
+	"atlas-character/equipment/statistics"
+	"atlas-character/job"
+	"context"
+	"fmt"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type RequirementReason string
+
+const (
+	RequirementReasonLevel        RequirementReason = "LEVEL"
+	RequirementReasonJob          RequirementReason = "JOB"
+	RequirementReasonStrength     RequirementReason = "STRENGTH"
+	RequirementReasonDexterity    RequirementReason = "DEXTERITY"
+	RequirementReasonIntelligence RequirementReason = "INTELLIGENCE"
+	RequirementReasonLuck         RequirementReason = "LUCK"
+	RequirementReasonGender       RequirementReason = "GENDER"
+)
+
+// RequirementError is returned when a character does not meet a requirement of the item being equipped.
+type RequirementError struct {
+	ItemId   uint32
+	Reason   RequirementReason
+	Required uint32
+	Actual   uint32
+}
+
+func (e RequirementError) Error() string {
+	return fmt.Sprintf("item [%d] requires %s [%d], character has [%d]", e.ItemId, e.Reason, e.Required, e.Actual)
+}
+
+// Wearer is the character attempting to equip an item. Statistics include bonuses from equipment currently worn, which
+// are also kept by the slot they are worn in.
+type Wearer struct {
+	Level        byte
+	JobId        uint16
+	Gender       byte
+	Strength     uint32
+	Dexterity    uint32
+	Intelligence uint32
+	Luck         uint32
+	Worn         map[int16]Bonus
+}
+
+// Bonus is the contribution of a worn piece of equipment to the statistics requirements are checked against.
+type Bonus struct {
+	Strength     uint32
+	Dexterity    uint32
+	Intelligence uint32
+	Luck         uint32
+}
+
+// Without gives the wearer as they would be once the equipment in the slot is taken off, as happens to the equipment
+// being replaced by an equip.
+func (w Wearer) Without(slot int16) Wearer {
+	b, ok := w.Worn[slot]
+	if !ok {
+		return w
+	}
+	w.Strength = subtract(w.Strength, b.Strength)
+	w.Dexterity = subtract(w.Dexterity, b.Dexterity)
+	w.Intelligence = subtract(w.Intelligence, b.Intelligence)
+	w.Luck = subtract(w.Luck, b.Luck)
+	return w
+}
+
+func subtract(v uint32, b uint32) uint32 {
+	if b > v {
+		return 0
+	}
+	return v - b
+}
+
+// WearerProvider supplies the Wearer for a character.
+type WearerProvider func(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(characterId uint32) model.Provider[Wearer]
+
+// Validator verifies whether an item may be equipped in the destination slot.
+type Validator func(itemId uint32, destination int16) error
+
+// ValidatorProvider supplies a Validator reading through db, which is the transaction of the equip. Validators must not
+// read through any other handle, as a single connection database would deadlock.
+type ValidatorProvider func(db *gorm.DB) Validator
+
+func NoOpValidator(_ *gorm.DB) Validator {
+	return func(_ uint32, _ int16) error {
+		return nil
+	}
+}
+
+// RequirementValidator validates items against the requirements given by game data, without the equipment they replace.
+// The wearer is expected to have been retrieved before the equip, so nothing is read through the transaction.
+func RequirementValidator(l logrus.FieldLogger) func(ctx context.Context) func(wearerProvider model.Provider[Wearer]) ValidatorProvider {
+	return func(ctx context.Context) func(wearerProvider model.Provider[Wearer]) ValidatorProvider {
+		return func(wearerProvider model.Provider[Wearer]) ValidatorProvider {
+			return func(_ *gorm.DB) Validator {
+				return func(itemId uint32, destination int16) error {
+					is, err := statistics.GetById(l, ctx)(itemId)
+					if err != nil {
+						l.WithError(err).Errorf("Unable to retrieve requirements for item [%d].", itemId)
+						return err
+					}
+					w, err := wearerProvider()
+					if err != nil {
+						return err
+					}
+					return ValidateRequirements(w.Without(destination))(itemId, is)
+				}
+			}
+		}
+	}
+}
+
+// Job requirement flags as given by game data. A requirement of 0 permits every job, including beginners.
+const (
+	requiredJobWarrior  uint16 = 1
+	requiredJobMagician uint16 = 2
+	requiredJobBowman   uint16 = 4
+	requiredJobThief    uint16 = 8
+	requiredJobPirate   uint16 = 16
+)
+
+func ValidateRequirements(w Wearer) func(itemId uint32, r statistics.Model) error {
+	return func(itemId uint32, r statistics.Model) error {
+		if w.Level < r.RequiredLevel() {
+			return RequirementError{ItemId: itemId, Reason: RequirementReasonLevel, Required: uint32(r.RequiredLevel()), Actual: uint32(w.Level)}
+		}
+		if r.RequiredJob() != 0 && r.RequiredJob()&jobFlag(w.JobId) == 0 {
+			return RequirementError{ItemId: itemId, Reason: RequirementReasonJob, Required: uint32(r.RequiredJob()), Actual: uint32(w.JobId)}
+		}
+		if w.Strength < uint32(r.RequiredStrength()) {
+			return RequirementError{ItemId: itemId, Reason: RequirementReasonStrength, Required: uint32(r.RequiredStrength()), Actual: w.Strength}
+		}
+		if w.Dexterity < uint32(r.RequiredDexterity()) {
+			return RequirementError{ItemId: itemId, Reason: RequirementReasonDexterity, Required: uint32(r.RequiredDexterity()), Actual: w.Dexterity}
+		}
+		if w.Intelligence < uint32(r.RequiredIntelligence()) {
+			return RequirementError{ItemId: itemId, Reason: RequirementReasonIntelligence, Required: uint32(r.RequiredIntelligence()), Actual: w.Intelligence}
+		}
+		if w.Luck < uint32(r.RequiredLuck()) {
+			return RequirementError{ItemId: itemId, Reason: RequirementReasonLuck, Required: uint32(r.RequiredLuck()), Actual: w.Luck}
+		}
+		if g, ok := requiredGender(itemId); ok && g != w.Gender {
+			return RequirementError{ItemId: itemId, Reason: RequirementReasonGender, Required: uint32(g), Actual: uint32(w.Gender)}
+		}
+		return nil
+	}
+}
+
+func jobFlag(jobId uint16) uint16 {
+	switch job.GetJobStyle(jobId, 0, 0) {
+	case job.Warrior:
+		return requiredJobWarrior
+	case job.Magician:
+		return requiredJobMagician
+	case job.Bowman, job.CrossBowman:
+		return requiredJobBowman
+	case job.Thief:
+		return requiredJobThief
+	case job.Brawler, job.Gunslinger:
+		return requiredJobPirate
+	}
+	return 0
+}
+
+// requiredGender determines the gender an item is restricted to. Equipment item ids encode 0 for male, 1 for female,
+// and anything else for either in their thousands digit.
+func requiredGender(itemId uint32) (byte, bool) {
+	if itemId/1000000 != 1 {
+		return 0, false
+	}
+	g := (itemId / 1000) % 10
+	if g == 0 || g == 1 {
+		return byte(g), true
+	}
+	return 0, false
+}
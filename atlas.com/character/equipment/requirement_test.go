@@ -0,0 +1,94 @@
+package equipment_test
+
+import (
+	"atlas-character/equipment"
+	"atlas-character/equipment/statistics"
+	"errors"
+	"testing"
+)
+
+func requirements(r statistics.RestModel) statistics.Model {
+	m, _ := statistics.Extract(r)
+	return m
+}
+
+func expectReason(t *testing.T, err error, reason equipment.RequirementReason) {
+	var re equipment.RequirementError
+	if !errors.As(err, &re) {
+		t.Fatalf("Expected requirement error, got: %v", err)
+	}
+	if re.Reason != reason {
+		t.Fatalf("Expected reason [%s], got [%s]", reason, re.Reason)
+	}
+}
+
+func TestRequirementLevel(t *testing.T) {
+	beginner := equipment.Wearer{Level: 1, JobId: 0, Strength: 12, Dexterity: 5, Intelligence: 4, Luck: 4}
+	err := equipment.ValidateRequirements(beginner)(1302000, requirements(statistics.RestModel{RequiredLevel: 120}))
+	expectReason(t, err, equipment.RequirementReasonLevel)
+}
+
+func TestRequirementJob(t *testing.T) {
+	r := requirements(statistics.RestModel{RequiredJob: 1})
+	beginner := equipment.Wearer{Level: 10, JobId: 0}
+	expectReason(t, equipment.ValidateRequirements(beginner)(1302000, r), equipment.RequirementReasonJob)
+
+	magician := equipment.Wearer{Level: 10, JobId: 200}
+	expectReason(t, equipment.ValidateRequirements(magician)(1302000, r), equipment.RequirementReasonJob)
+
+	for _, jobId := range []uint16{100, 112, 1100, 2100} {
+		w := equipment.Wearer{Level: 10, JobId: jobId}
+		if err := equipment.ValidateRequirements(w)(1302000, r); err != nil {
+			t.Fatalf("Job [%d] should be able to equip warrior items: %v", jobId, err)
+		}
+	}
+}
+
+func TestRequirementStatistics(t *testing.T) {
+	r := requirements(statistics.RestModel{RequiredStrength: 35, RequiredDexterity: 20, RequiredIntelligence: 10, RequiredLuck: 15})
+	w := equipment.Wearer{Level: 10, Strength: 34, Dexterity: 20, Intelligence: 10, Luck: 15}
+	expectReason(t, equipment.ValidateRequirements(w)(1302000, r), equipment.RequirementReasonStrength)
+	w.Strength = 35
+	w.Dexterity = 19
+	expectReason(t, equipment.ValidateRequirements(w)(1302000, r), equipment.RequirementReasonDexterity)
+	w.Dexterity = 20
+	w.Intelligence = 9
+	expectReason(t, equipment.ValidateRequirements(w)(1302000, r), equipment.RequirementReasonIntelligence)
+	w.Intelligence = 10
+	w.Luck = 14
+	expectReason(t, equipment.ValidateRequirements(w)(1302000, r), equipment.RequirementReasonLuck)
+	w.Luck = 15
+	if err := equipment.ValidateRequirements(w)(1302000, r); err != nil {
+		t.Fatalf("Requirements should be met: %v", err)
+	}
+}
+
+func TestRequirementGender(t *testing.T) {
+	r := requirements(statistics.RestModel{})
+	male := equipment.Wearer{Level: 1, Gender: 0}
+	female := equipment.Wearer{Level: 1, Gender: 1}
+	expectReason(t, equipment.ValidateRequirements(male)(1041002, r), equipment.RequirementReasonGender)
+	expectReason(t, equipment.ValidateRequirements(female)(1040002, r), equipment.RequirementReasonGender)
+	if err := equipment.ValidateRequirements(female)(1041002, r); err != nil {
+		t.Fatalf("Female should be able to equip female top: %v", err)
+	}
+	if err := equipment.ValidateRequirements(male)(1302000, r); err != nil {
+		t.Fatalf("Weapons are not gender restricted: %v", err)
+	}
+}
+
+func TestRequirementWithoutReplaced(t *testing.T) {
+	r := requirements(statistics.RestModel{RequiredStrength: 35})
+	w := equipment.Wearer{Level: 10, Strength: 40, Worn: map[int16]equipment.Bonus{-11: {Strength: 10}, -5: {Dexterity: 3}}}
+	if err := equipment.ValidateRequirements(w.Without(-5))(1302000, r); err != nil {
+		t.Fatalf("Requirements should be met without the top: %v", err)
+	}
+	// A weapon replacing the one granting the strength cannot count on it.
+	expectReason(t, equipment.ValidateRequirements(w.Without(-11))(1302000, r), equipment.RequirementReasonStrength)
+	if s := w.Without(-11).Strength; s != 30 {
+		t.Fatalf("Strength should be 30, was %d.", s)
+	}
+	if d := w.Without(-5).Dexterity; d != 0 {
+		t.Fatalf("Dexterity should not fall below 0, was %d.", d)
+	}
+}
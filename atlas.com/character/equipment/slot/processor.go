@@ -73,3 +73,20 @@ func PositionFromType(slotType string) (Position, error) {
 	}
 	return PositionHat, errors.New("unable to map type to position")
 }
+
+// Positions are the equipment positions an equipable may be worn in.
+var Positions = []Position{PositionHat, PositionMedal, PositionForehead, PositionRing1, PositionRing2, PositionEye, PositionEarring, PositionShoulder, PositionCape, PositionTop, PositionPendant, PositionWeapon, PositionShield, PositionGloves, PositionBottom, PositionBelt, PositionRing3, PositionRing4, PositionShoes}
+
+// IsEquipmentSlot reports whether an equipable in the given slot is worn, either in a regular equipment position or in
+// the cash equipment position 100 slots below it.
+func IsEquipmentSlot(s int16) bool {
+	if s <= -100 {
+		s += 100
+	}
+	for _, p := range Positions {
+		if p < 0 && Position(s) == p {
+			return true
+		}
+	}
+	return false
+}
@@ -1,23 +1,30 @@
 package statistics
 
 type Model struct {
-	strength      uint16
-	dexterity     uint16
-	intelligence  uint16
-	luck          uint16
-	hp            uint16
-	mp            uint16
-	weaponAttack  uint16
-	magicAttack   uint16
-	weaponDefense uint16
-	magicDefense  uint16
-	accuracy      uint16
-	avoidability  uint16
-	hands         uint16
-	speed         uint16
-	jump          uint16
-	slots         uint16
-	cash          bool
+	strength             uint16
+	dexterity            uint16
+	intelligence         uint16
+	luck                 uint16
+	hp                   uint16
+	mp                   uint16
+	weaponAttack         uint16
+	magicAttack          uint16
+	weaponDefense        uint16
+	magicDefense         uint16
+	accuracy             uint16
+	avoidability         uint16
+	hands                uint16
+	speed                uint16
+	jump                 uint16
+	slots                uint16
+	cash                 bool
+	requiredLevel        byte
+	requiredJob          uint16
+	requiredStrength     uint16
+	requiredDexterity    uint16
+	requiredIntelligence uint16
+	requiredLuck         uint16
+	twoHanded            bool
 }
 
 func (m Model) Strength() uint16 {
@@ -87,3 +94,31 @@ func (m Model) Slots() uint16 {
 func (m Model) Cash() bool {
 	return m.cash
 }
+
+func (m Model) RequiredLevel() byte {
+	return m.requiredLevel
+}
+
+func (m Model) RequiredJob() uint16 {
+	return m.requiredJob
+}
+
+func (m Model) RequiredStrength() uint16 {
+	return m.requiredStrength
+}
+
+func (m Model) RequiredDexterity() uint16 {
+	return m.requiredDexterity
+}
+
+func (m Model) RequiredIntelligence() uint16 {
+	return m.requiredIntelligence
+}
+
+func (m Model) RequiredLuck() uint16 {
+	return m.requiredLuck
+}
+
+func (m Model) TwoHanded() bool {
+	return m.twoHanded
+}
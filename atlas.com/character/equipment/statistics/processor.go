@@ -1,15 +1,18 @@
 package statistics
 
 import (
+	"atlas-character/gamedata"
 	"context"
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/Chronicle20/atlas-rest/requests"
 	"github.com/sirupsen/logrus"
 )
 
+var cache = gamedata.NewCache[uint32, Model]()
+
 func ByIdModelProvider(l logrus.FieldLogger, ctx context.Context) func(id uint32) model.Provider[Model] {
 	return func(id uint32) model.Provider[Model] {
-		return requests.Provider[RestModel, Model](l, ctx)(requestById(id), Extract)
+		return cache.Provider(ctx, id, requests.Provider[RestModel, Model](l, ctx)(requestById(id), Extract))
 	}
 }
 
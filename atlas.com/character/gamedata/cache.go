@@ -0,0 +1,139 @@
+package gamedata
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-tenant"
+	"golang.org/x/sync/singleflight"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// defaultCapacity is the number of entries a cache holds when GAME_DATA_CACHE_SIZE is not set.
+const defaultCapacity = 10000
+
+// Version identifies a tenant's game data. Game data never changes within a version, so tenants sharing a region and
+// version share cached game data, and cached entries never expire.
+type Version struct {
+	Region       string
+	MajorVersion uint16
+	MinorVersion uint16
+}
+
+func VersionFromContext(ctx context.Context) Version {
+	t := tenant.MustFromContext(ctx)
+	return Version{Region: t.Region(), MajorVersion: t.MajorVersion(), MinorVersion: t.MinorVersion()}
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%s-%d.%d", v.Region, v.MajorVersion, v.MinorVersion)
+}
+
+type cacheKey[K comparable] struct {
+	version Version
+	key     K
+}
+
+type cacheEntry[K comparable, M any] struct {
+	key   cacheKey[K]
+	value M
+}
+
+// Cache is a read-through cache of game data keyed by version. It holds at most capacity entries, evicting the least
+// recently used. Concurrent misses of the same entry are retrieved once.
+type Cache[K comparable, M any] struct {
+	capacity int
+	mutex    sync.Mutex
+	entries  map[cacheKey[K]]*list.Element
+	order    *list.List
+	group    singleflight.Group
+}
+
+// NewCache creates a cache holding up to GAME_DATA_CACHE_SIZE entries.
+func NewCache[K comparable, M any]() *Cache[K, M] {
+	capacity := defaultCapacity
+	if val, ok := os.LookupEnv("GAME_DATA_CACHE_SIZE"); ok {
+		if size, err := strconv.Atoi(val); err == nil && size > 0 {
+			capacity = size
+		}
+	}
+	return NewCacheWithCapacity[K, M](capacity)
+}
+
+func NewCacheWithCapacity[K comparable, M any](capacity int) *Cache[K, M] {
+	return &Cache[K, M]{
+		capacity: capacity,
+		entries:  make(map[cacheKey[K]]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get yields the entry for key in the context tenant's version, retrieving it with provider on a miss. Failed
+// retrievals are not cached.
+func (c *Cache[K, M]) Get(ctx context.Context, key K, provider model.Provider[M]) (M, error) {
+	ck := cacheKey[K]{version: VersionFromContext(ctx), key: key}
+	if m, ok := c.get(ck); ok {
+		return m, nil
+	}
+
+	res, err, _ := c.group.Do(fmt.Sprintf("%s/%v", ck.version, key), func() (interface{}, error) {
+		if m, ok := c.get(ck); ok {
+			return m, nil
+		}
+		m, err := provider()
+		if err != nil {
+			return nil, err
+		}
+		c.put(ck, m)
+		return m, nil
+	})
+	if err != nil {
+		var m M
+		return m, err
+	}
+	return res.(M), nil
+}
+
+// Provider yields a provider which reads through the cache.
+func (c *Cache[K, M]) Provider(ctx context.Context, key K, provider model.Provider[M]) model.Provider[M] {
+	return func() (M, error) {
+		return c.Get(ctx, key, provider)
+	}
+}
+
+func (c *Cache[K, M]) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+func (c *Cache[K, M]) get(key cacheKey[K]) (M, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		var m M
+		return m, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(cacheEntry[K, M]).value, true
+}
+
+func (c *Cache[K, M]) put(key cacheKey[K], value M) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.Value = cacheEntry[K, M]{key: key, value: value}
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(cacheEntry[K, M]{key: key, value: value})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(cacheEntry[K, M]).key)
+	}
+}
@@ -0,0 +1,96 @@
+package gamedata_test
+
+import (
+	"atlas-character/gamedata"
+	"context"
+	"errors"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testContext(region string, majorVersion uint16) context.Context {
+	t, _ := tenant.Create(uuid.New(), region, majorVersion, 1)
+	return tenant.WithContext(context.Background(), t)
+}
+
+func countingProvider(calls *int32, value string) func() (string, error) {
+	return func() (string, error) {
+		atomic.AddInt32(calls, 1)
+		time.Sleep(10 * time.Millisecond)
+		return value, nil
+	}
+}
+
+func TestCacheSharedByVersion(t *testing.T) {
+	c := gamedata.NewCacheWithCapacity[uint32, string](10)
+	var calls int32
+
+	v, err := c.Get(testContext("GMS", 83), 1, countingProvider(&calls, "a"))
+	if err != nil || v != "a" {
+		t.Fatalf("Unexpected entry: %s %v", v, err)
+	}
+	// A different tenant on the same version shares the entry.
+	v, err = c.Get(testContext("GMS", 83), 1, countingProvider(&calls, "b"))
+	if err != nil || v != "a" || calls != 1 {
+		t.Fatalf("Expected entry to be shared by version, got: %s after %d calls", v, calls)
+	}
+	v, err = c.Get(testContext("GMS", 87), 1, countingProvider(&calls, "c"))
+	if err != nil || v != "c" || calls != 2 {
+		t.Fatalf("Expected versions to be cached separately, got: %s after %d calls", v, calls)
+	}
+}
+
+func TestCacheSingleflight(t *testing.T) {
+	c := gamedata.NewCacheWithCapacity[uint32, string](10)
+	ctx := testContext("GMS", 83)
+	var calls int32
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Get(ctx, 1, countingProvider(&calls, "a")); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Fatalf("Expected concurrent misses to be retrieved once, got %d calls.", calls)
+	}
+}
+
+func TestCacheBounds(t *testing.T) {
+	c := gamedata.NewCacheWithCapacity[uint32, string](2)
+	ctx := testContext("GMS", 83)
+	var calls int32
+
+	_, _ = c.Get(ctx, 1, countingProvider(&calls, "a"))
+	_, _ = c.Get(ctx, 2, countingProvider(&calls, "b"))
+	_, _ = c.Get(ctx, 1, countingProvider(&calls, "a"))
+	_, _ = c.Get(ctx, 3, countingProvider(&calls, "c"))
+	if c.Len() != 2 {
+		t.Fatalf("Expected cache to be bounded, holds %d entries.", c.Len())
+	}
+	// 2 was least recently used and evicted, 1 remains.
+	_, _ = c.Get(ctx, 1, countingProvider(&calls, "a"))
+	if calls != 3 {
+		t.Fatalf("Expected recently used entry to remain cached, got %d calls.", calls)
+	}
+	_, _ = c.Get(ctx, 2, countingProvider(&calls, "b"))
+	if calls != 4 {
+		t.Fatalf("Expected least recently used entry to be evicted, got %d calls.", calls)
+	}
+
+	_, err := c.Get(ctx, 4, func() (string, error) {
+		return "", errors.New("unavailable")
+	})
+	if err == nil || c.Len() != 2 {
+		t.Fatalf("Expected failure not to be cached.")
+	}
+}
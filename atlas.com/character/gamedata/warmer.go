@@ -0,0 +1,26 @@
+package gamedata
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+)
+
+// Warmer retrieves game data ahead of its first use, so that it is served from the caches.
+type Warmer func(l logrus.FieldLogger, ctx context.Context) error
+
+// Warm runs the warmers for the context tenant's version. Failures are only logged, as a cold cache merely costs the
+// round trips warming would have saved.
+func Warm(l logrus.FieldLogger) func(ctx context.Context) func(warmers ...Warmer) {
+	return func(ctx context.Context) func(warmers ...Warmer) {
+		return func(warmers ...Warmer) {
+			v := VersionFromContext(ctx)
+			for _, w := range warmers {
+				err := w(l, ctx)
+				if err != nil {
+					l.WithError(err).Warnf("Unable to warm game data for version [%s].", v)
+				}
+			}
+			l.Infof("Warmed game data for version [%s].", v)
+		}
+	}
+}
@@ -27,3 +27,7 @@ func deleteByType(db *gorm.DB, tenantId uuid.UUID, characterId uint32, inventory
 func delete(db *gorm.DB, tenantId uuid.UUID, id uint32) error {
 	return db.Where(&entity{TenantId: tenantId, ID: id}).Delete(&entity{}).Error
 }
+
+func updateCapacity(db *gorm.DB, tenantId uuid.UUID, id uint32, capacity uint32) error {
+	return db.Model(&entity{}).Where(&entity{TenantId: tenantId, ID: id}).Update("capacity", capacity).Error
+}
@@ -1,7 +1,9 @@
 package inventory
 
 import (
+	"atlas-character/configuration"
 	"atlas-character/equipable"
+	"atlas-character/equipable/statistics"
 	"atlas-character/equipment"
 	consumer2 "atlas-character/kafka/consumer"
 	"atlas-character/kafka/producer"
@@ -11,6 +13,7 @@ import (
 	"github.com/Chronicle20/atlas-kafka/message"
 	"github.com/Chronicle20/atlas-kafka/topic"
 	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-tenant"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -20,6 +23,12 @@ const (
 	consumerUnequipItem = "unequip_item_command"
 	consumerMoveItem    = "move_item_command"
 	consumerDropItem    = "drop_item_command"
+	consumerPickupItem  = "pickup_item_command"
+	consumerExpand      = "expand_inventory_command"
+	consumerConsumeItem = "consume_item_command"
+	consumerSplitItem   = "split_item_command"
+	consumerSort        = "sort_inventory_command"
+	consumerGather      = "gather_inventory_command"
 )
 
 func EquipItemCommandConsumer(l logrus.FieldLogger) func(groupId string) consumer.Config {
@@ -28,18 +37,49 @@ func EquipItemCommandConsumer(l logrus.FieldLogger) func(groupId string) consume
 	}
 }
 
-func EquipItemRegister(l logrus.FieldLogger, db *gorm.DB) (string, handler.Handler) {
+func EquipItemRegister(l logrus.FieldLogger, db *gorm.DB, wp equipment.WearerProvider, asp equipment.AvailableSlotsProvider) (string, handler.Handler) {
 	t, _ := topic.EnvProvider(l)(EnvCommandTopicEquipItem)()
-	return t, message.AdaptHandler(message.PersistentConfig(handleEquipItemCommand(db)))
+	return t, message.AdaptHandler(message.PersistentConfig(handleEquipItemCommand(db, wp, asp)))
 }
 
-func handleEquipItemCommand(db *gorm.DB) message.Handler[equipItemCommand] {
+func handleEquipItemCommand(db *gorm.DB, wp equipment.WearerProvider, asp equipment.AvailableSlotsProvider) message.Handler[equipItemCommand] {
 	return func(l logrus.FieldLogger, ctx context.Context, command equipItemCommand) {
 		l.Debugf("Received equip item command. characterId [%d] source [%d] destination [%d]", command.CharacterId, command.Source, command.Destination)
-		fsp := model.Flip(equipable.GetNextFreeSlot(l))(ctx)
+		fsp := model.Flip(equipable.GetNextFreeSlot(l)(GetCapacity))(ctx)
 		ep := producer.ProviderImpl(l)(ctx)
-		dp := equipment.GetEquipmentDestination(l)(ctx)
-		EquipItemForCharacter(l)(db)(ctx)(fsp)(ep)(command.CharacterId)(command.Source)(dp)
+		thp := equipment.GetTwoHandedProvider(l)(ctx)
+		// The wearer and available slots are retrieved up front, so that their lookups are not made while the inventory
+		// is locked. The item's requirements and whether weapons are two-handed depend on what is in the slots, so are
+		// only known once locked, and are retrieved from game data inside the lock and transaction when not cached.
+		w, err := wp(l)(db.WithContext(ctx))(ctx)(command.CharacterId)()
+		if err != nil {
+			l.WithError(err).Errorf("Unable to retrieve character [%d] equipping item.", command.CharacterId)
+			rejectEquip(l)(ep)(command.CharacterId, command.Source, err)
+			return
+		}
+		available, err := asp(l)(ctx)()
+		if err != nil {
+			l.WithError(err).Errorf("Unable to determine equipment slots available to character [%d].", command.CharacterId)
+			rejectEquip(l)(ep)(command.CharacterId, command.Source, err)
+			return
+		}
+		dp := equipment.GetEquipmentDestination(l)(ctx)(available)
+		rv := equipment.RequirementValidator(l)(ctx)(model.FixedProvider(w))
+		err = EquipItemForCharacter(l)(db.WithContext(ctx))(ctx)(fsp)(thp)(ep)(command.CharacterId)(command.Source)(dp, rv)
+		if err != nil {
+			rejectEquip(l)(ep)(command.CharacterId, command.Source, err)
+		}
+	}
+}
+
+func rejectEquip(l logrus.FieldLogger) func(eventProducer producer.Provider) func(characterId uint32, source int16, cause error) {
+	return func(eventProducer producer.Provider) func(characterId uint32, source int16, cause error) {
+		return func(characterId uint32, source int16, cause error) {
+			err := eventProducer(EnvEventInventoryChanged)(inventoryEquipRejectedProvider(characterId, source, cause))
+			if err != nil {
+				l.WithError(err).Errorf("Unable to convey rejected equip of slot [%d] to character [%d].", source, characterId)
+			}
+		}
 	}
 }
 
@@ -57,9 +97,9 @@ func UnequipItemRegister(l logrus.FieldLogger, db *gorm.DB) (string, handler.Han
 func handleUnequipItemCommand(db *gorm.DB) message.Handler[unequipItemCommand] {
 	return func(l logrus.FieldLogger, ctx context.Context, command unequipItemCommand) {
 		l.Debugf("Received unequip item command. characterId [%d] source [%d].", command.CharacterId, command.Source)
-		fsp := model.Flip(equipable.GetNextFreeSlot(l))(ctx)
+		fsp := model.Flip(equipable.GetNextFreeSlot(l)(GetCapacity))(ctx)
 		ep := producer.ProviderImpl(l)(ctx)
-		UnequipItemForCharacter(l)(db)(ctx)(fsp)(ep)(command.CharacterId)(command.Source)
+		UnequipItemForCharacter(l)(db.WithContext(ctx))(ctx)(fsp)(ep)(command.CharacterId)(command.Source)
 	}
 }
 
@@ -76,7 +116,24 @@ func MoveItemRegister(l logrus.FieldLogger, db *gorm.DB) (string, handler.Handle
 
 func handleMoveItemCommand(db *gorm.DB) message.Handler[moveItemCommand] {
 	return func(l logrus.FieldLogger, ctx context.Context, command moveItemCommand) {
-		_ = Move(l)(db)(ctx)(producer.ProviderImpl(l)(ctx))(command.InventoryType)(command.CharacterId)(command.Source)(command.Destination)
+		_ = Move(l)(db.WithContext(ctx))(ctx)(producer.ProviderImpl(l)(ctx))(ItemSlotMaxProvider(l)(ctx))(command.InventoryType)(command.CharacterId)(command.Source)(command.Destination)
+	}
+}
+
+func SplitItemCommandConsumer(l logrus.FieldLogger) func(groupId string) consumer.Config {
+	return func(groupId string) consumer.Config {
+		return consumer2.NewConfig(l)(consumerSplitItem)(EnvCommandTopicSplitItem)(groupId)
+	}
+}
+
+func SplitItemRegister(l logrus.FieldLogger, db *gorm.DB) (string, handler.Handler) {
+	t, _ := topic.EnvProvider(l)(EnvCommandTopicSplitItem)()
+	return t, message.AdaptHandler(message.PersistentConfig(handleSplitItemCommand(db)))
+}
+
+func handleSplitItemCommand(db *gorm.DB) message.Handler[splitItemCommand] {
+	return func(l logrus.FieldLogger, ctx context.Context, command splitItemCommand) {
+		_ = Split(l)(db.WithContext(ctx))(ctx)(producer.ProviderImpl(l)(ctx))(command.CharacterId, Type(command.InventoryType), command.Source, command.Destination, command.Quantity)
 	}
 }
 
@@ -86,13 +143,136 @@ func DropItemCommandConsumer(l logrus.FieldLogger) func(groupId string) consumer
 	}
 }
 
-func DropItemRegister(l logrus.FieldLogger, db *gorm.DB) (string, handler.Handler) {
+func DropItemRegister(l logrus.FieldLogger, db *gorm.DB, pp PositionProvider) (string, handler.Handler) {
 	t, _ := topic.EnvProvider(l)(EnvCommandTopicDropItem)()
-	return t, message.AdaptHandler(message.PersistentConfig(handleDropItemCommand(db)))
+	return t, message.AdaptHandler(message.PersistentConfig(handleDropItemCommand(db, pp)))
 }
 
-func handleDropItemCommand(db *gorm.DB) message.Handler[dropItemCommand] {
+func handleDropItemCommand(db *gorm.DB, pp PositionProvider) message.Handler[dropItemCommand] {
 	return func(l logrus.FieldLogger, ctx context.Context, command dropItemCommand) {
-		_ = Drop(l)(db)(ctx)(command.InventoryType)(command.CharacterId)(command.Source)(command.Quantity)
+		f := Field{WorldId: command.WorldId, ChannelId: command.ChannelId, MapId: command.MapId}
+		_ = Drop(l)(db.WithContext(ctx))(ctx)(producer.ProviderImpl(l)(ctx))(pp)(f)(command.InventoryType)(command.CharacterId)(command.Source)(command.Quantity)
+	}
+}
+
+func PickupItemCommandConsumer(l logrus.FieldLogger) func(groupId string) consumer.Config {
+	return func(groupId string) consumer.Config {
+		return consumer2.NewConfig(l)(consumerPickupItem)(EnvCommandTopicPickupItem)(groupId)
+	}
+}
+
+func PickupItemRegister(l logrus.FieldLogger, db *gorm.DB) (string, handler.Handler) {
+	t, _ := topic.EnvProvider(l)(EnvCommandTopicPickupItem)()
+	return t, message.AdaptHandler(message.PersistentConfig(handlePickupItemCommand(db)))
+}
+
+func handlePickupItemCommand(db *gorm.DB) message.Handler[pickupItemCommand] {
+	return func(l logrus.FieldLogger, ctx context.Context, command pickupItemCommand) {
+		l.Debugf("Received pickup item command. characterId [%d] itemId [%d] quantity [%d] referenceId [%d].", command.CharacterId, command.ItemId, command.Quantity, command.ReferenceId)
+		sc := statistics.Create(l)(ctx)
+		if command.ReferenceId != 0 {
+			sc = statistics.Existing(l)(ctx)(command.ReferenceId)
+		}
+		ep := producer.ProviderImpl(l)(ctx)
+		err := Pickup(l)(db.WithContext(ctx))(ctx)(ep)(ItemSlotMaxProvider(l)(ctx))(sc)(command.CharacterId, command.ItemId, command.Quantity, command.ReferenceId)
+		if err != nil {
+			err = ep(EnvEventInventoryChanged)(inventoryPickupRejectedProvider(command.CharacterId, command.ItemId, command.Quantity, command.ReferenceId, err))
+			if err != nil {
+				l.WithError(err).Errorf("Unable to convey rejected pickup of item [%d] to character [%d].", command.ItemId, command.CharacterId)
+			}
+		}
+	}
+}
+
+func ConsumeItemCommandConsumer(l logrus.FieldLogger) func(groupId string) consumer.Config {
+	return func(groupId string) consumer.Config {
+		return consumer2.NewConfig(l)(consumerConsumeItem)(EnvCommandTopicConsumeItem)(groupId)
+	}
+}
+
+func ConsumeItemRegister(l logrus.FieldLogger, db *gorm.DB) (string, handler.Handler) {
+	t, _ := topic.EnvProvider(l)(EnvCommandTopicConsumeItem)()
+	return t, message.AdaptHandler(message.PersistentConfig(handleConsumeItemCommand(db)))
+}
+
+func handleConsumeItemCommand(db *gorm.DB) message.Handler[consumeItemCommand] {
+	return func(l logrus.FieldLogger, ctx context.Context, command consumeItemCommand) {
+		l.Debugf("Received consume item command. characterId [%d] inventoryType [%d] source [%d] quantity [%d].", command.CharacterId, command.InventoryType, command.Source, command.Quantity)
+		_ = Consume(l)(db.WithContext(ctx))(ctx)(producer.ProviderImpl(l)(ctx))(command.CharacterId, Type(command.InventoryType), command.Source, command.Quantity)
+	}
+}
+
+func SortCommandConsumer(l logrus.FieldLogger) func(groupId string) consumer.Config {
+	return func(groupId string) consumer.Config {
+		return consumer2.NewConfig(l)(consumerSort)(EnvCommandTopicSort)(groupId)
+	}
+}
+
+func SortRegister(l logrus.FieldLogger, db *gorm.DB) (string, handler.Handler) {
+	t, _ := topic.EnvProvider(l)(EnvCommandTopicSort)()
+	return t, message.AdaptHandler(message.PersistentConfig(handleSortCommand(db)))
+}
+
+func handleSortCommand(db *gorm.DB) message.Handler[sortInventoryCommand] {
+	return func(l logrus.FieldLogger, ctx context.Context, command sortInventoryCommand) {
+		_ = Sort(l)(db.WithContext(ctx))(ctx)(producer.ProviderImpl(l)(ctx))(ItemSlotMaxProvider(l)(ctx))(command.CharacterId, Type(command.InventoryType))
+	}
+}
+
+func GatherCommandConsumer(l logrus.FieldLogger) func(groupId string) consumer.Config {
+	return func(groupId string) consumer.Config {
+		return consumer2.NewConfig(l)(consumerGather)(EnvCommandTopicGather)(groupId)
+	}
+}
+
+func GatherRegister(l logrus.FieldLogger, db *gorm.DB) (string, handler.Handler) {
+	t, _ := topic.EnvProvider(l)(EnvCommandTopicGather)()
+	return t, message.AdaptHandler(message.PersistentConfig(handleGatherCommand(db)))
+}
+
+func handleGatherCommand(db *gorm.DB) message.Handler[gatherInventoryCommand] {
+	return func(l logrus.FieldLogger, ctx context.Context, command gatherInventoryCommand) {
+		_ = Gather(l)(db.WithContext(ctx))(ctx)(producer.ProviderImpl(l)(ctx))(command.CharacterId, Type(command.InventoryType))
+	}
+}
+
+func ExpandCommandConsumer(l logrus.FieldLogger) func(groupId string) consumer.Config {
+	return func(groupId string) consumer.Config {
+		return consumer2.NewConfig(l)(consumerExpand)(EnvCommandTopicExpand)(groupId)
+	}
+}
+
+func ExpandRegister(l logrus.FieldLogger, db *gorm.DB) (string, handler.Handler) {
+	t, _ := topic.EnvProvider(l)(EnvCommandTopicExpand)()
+	return t, message.AdaptHandler(message.PersistentConfig(handleExpandCommand(db)))
+}
+
+func handleExpandCommand(db *gorm.DB) message.Handler[expandInventoryCommand] {
+	return func(l logrus.FieldLogger, ctx context.Context, command expandInventoryCommand) {
+		l.Debugf("Received expand inventory command. characterId [%d] inventoryType [%d].", command.CharacterId, command.InventoryType)
+		_ = Expand(l)(db.WithContext(ctx))(ctx)(producer.ProviderImpl(l)(ctx))(command.CharacterId, Type(command.InventoryType), maxCapacity(ctx))
+	}
+}
+
+// maxCapacity determines the most slots an inventory may have in the tenant's version.
+func maxCapacity(ctx context.Context) uint32 {
+	t := tenant.MustFromContext(ctx)
+	tc, err := configuration.Get().FindTenant(t.Id())
+	if err != nil || tc.Characters.MaxInventoryCapacity == 0 {
+		return DefaultMaxCapacity
+	}
+	return tc.Characters.MaxInventoryCapacity
+}
+
+// ConfiguredAvailableSlots makes the equipment slots which exist for the tenant's version available to every character.
+// It is the default equipment.AvailableSlotsProvider.
+func ConfiguredAvailableSlots(_ logrus.FieldLogger) func(ctx context.Context) model.Provider[equipment.SlotPredicate] {
+	return func(ctx context.Context) model.Provider[equipment.SlotPredicate] {
+		t := tenant.MustFromContext(ctx)
+		tc, err := configuration.Get().FindTenant(t.Id())
+		if err != nil {
+			return model.ErrorProvider[equipment.SlotPredicate](err)
+		}
+		return model.FixedProvider(equipment.LockedSlots(tc.Characters.LockedEquipmentSlots...))
 	}
 }
@@ -10,11 +10,21 @@ func Migration(db *gorm.DB) error {
 }
 
 type entity struct {
-	TenantId      uuid.UUID `gorm:"not null"`
+	TenantId      uuid.UUID `gorm:"not null;uniqueIndex:idx_inventory_character_type"`
 	ID            uint32    `gorm:"primaryKey;autoIncrement;not null"`
-	CharacterId   uint32    `gorm:"not null;UNIQUE_INDEX:composite_index;index"`
-	InventoryType int8      `gorm:"not null;UNIQUE_INDEX:composite_index;"`
+	CharacterId   uint32    `gorm:"not null;uniqueIndex:idx_inventory_character_type;index"`
+	InventoryType int8      `gorm:"not null;uniqueIndex:idx_inventory_character_type"`
 	Capacity      uint32    `gorm:"capacity"`
+	Character     character `gorm:"foreignKey:CharacterId;constraint:OnDelete:CASCADE"`
+}
+
+// character is the owner of an inventory. It is only declared so that the foreign key is created, and is never loaded.
+type character struct {
+	ID uint32 `gorm:"primaryKey;autoIncrement;not null"`
+}
+
+func (e character) TableName() string {
+	return "characters"
 }
 
 func (e entity) TableName() string {
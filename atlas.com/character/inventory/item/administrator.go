@@ -21,7 +21,7 @@ func createItem(db *gorm.DB, tenantId uuid.UUID, inventoryId uint32, itemId uint
 }
 
 func deleteById(db *gorm.DB, tenantId uuid.UUID, id uint32) error {
-	return db.Where(&entity{TenantId: tenantId, ID: id}).Delete(&entity{}).Error
+	return affected(db.Where(&entity{TenantId: tenantId, ID: id}).Delete(&entity{}))
 }
 
 func makeModel(e entity) (Model, error) {
@@ -33,14 +33,25 @@ func makeModel(e entity) (Model, error) {
 	}, nil
 }
 
-func remove(db *gorm.DB, inventoryId uint32, id uint32) error {
-	return db.Delete(&entity{InventoryId: inventoryId, ID: id}).Error
+func remove(db *gorm.DB, tenantId uuid.UUID, inventoryId uint32, id uint32) error {
+	return affected(db.Where(&entity{TenantId: tenantId, InventoryId: inventoryId, ID: id}).Delete(&entity{}))
 }
 
-func updateQuantity(db *gorm.DB, id uint32, amount uint32) error {
-	return db.Model(&entity{ID: id}).Select("Quantity").Updates(&entity{Quantity: amount}).Error
+func updateQuantity(db *gorm.DB, tenantId uuid.UUID, id uint32, amount uint32) error {
+	return affected(db.Model(&entity{}).Where(&entity{TenantId: tenantId, ID: id}).Update("quantity", amount))
 }
 
-func updateSlot(db *gorm.DB, id uint32, slot int16) error {
-	return db.Model(&entity{ID: id}).Select("Slot").Updates(&entity{Slot: slot}).Error
+func updateSlot(db *gorm.DB, tenantId uuid.UUID, id uint32, slot int16) error {
+	return affected(db.Model(&entity{}).Where(&entity{TenantId: tenantId, ID: id}).Update("slot", slot))
+}
+
+// affected fails a write addressing an item which does not exist, or which belongs to another tenant.
+func affected(res *gorm.DB) error {
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
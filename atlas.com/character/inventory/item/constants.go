@@ -98,3 +98,33 @@ func GetWeaponDamageMultiplier(weaponType int) float64 {
 	}
 	return 0
 }
+
+// IsTwoHanded reports whether the weapon type of the item occupies both hands, preventing a shield from being worn.
+func IsTwoHanded(itemId uint32) bool {
+	switch GetWeaponType(itemId) {
+	case WeaponTypeGeneralTwoHandedSwing,
+		WeaponTypeGeneralTwoHandedStab,
+		WeaponTypeBow,
+		WeaponTypeClaw,
+		WeaponTypeCrossbow,
+		WeaponTypeGun,
+		WeaponTypeKnuckle,
+		WeaponTypePoleArmSwing,
+		WeaponTypePoleArmStab,
+		WeaponTypeSpearStab,
+		WeaponTypeSpearSwing,
+		WeaponTypeSwordTwoHanded:
+		return true
+	}
+	return false
+}
+
+// IsThrowingStar reports whether the item is a throwing star.
+func IsThrowingStar(itemId uint32) bool {
+	return itemId/10000 == 207
+}
+
+// IsBullet reports whether the item is a bullet.
+func IsBullet(itemId uint32) bool {
+	return itemId/10000 == 233
+}
@@ -12,10 +12,21 @@ func Migration(db *gorm.DB) error {
 type entity struct {
 	TenantId    uuid.UUID `gorm:"not null"`
 	ID          uint32    `gorm:"primaryKey;autoIncrement;not null"`
-	InventoryId uint32    `gorm:"not null"`
+	InventoryId uint32    `gorm:"not null;uniqueIndex:idx_items_inventory_slot"`
 	ItemId      uint32    `gorm:"not null"`
-	Slot        int16     `gorm:"not null"`
+	Slot        int16     `gorm:"not null;uniqueIndex:idx_items_inventory_slot"`
 	Quantity    uint32    `gorm:"not null"`
+	Inventory   inventory `gorm:"foreignKey:InventoryId;constraint:OnDelete:CASCADE"`
+}
+
+// inventory is the inventory holding an item. It is only declared so that the foreign key is created, and is never
+// loaded.
+type inventory struct {
+	ID uint32 `gorm:"primaryKey;autoIncrement;not null"`
+}
+
+func (e inventory) TableName() string {
+	return "inventory"
 }
 
 func (e entity) TableName() string {
@@ -0,0 +1,14 @@
+package information
+
+type Model struct {
+	itemId  uint32
+	slotMax uint32
+}
+
+func (m Model) ItemId() uint32 {
+	return m.itemId
+}
+
+func (m Model) SlotMax() uint32 {
+	return m.slotMax
+}
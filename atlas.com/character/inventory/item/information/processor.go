@@ -0,0 +1,35 @@
+package information
+
+import (
+	"atlas-character/gamedata"
+	"context"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/requests"
+	"github.com/sirupsen/logrus"
+)
+
+var cache = gamedata.NewCache[uint32, Model]()
+
+func byIdModelProvider(l logrus.FieldLogger, ctx context.Context) func(id uint32) model.Provider[Model] {
+	return func(id uint32) model.Provider[Model] {
+		req, err := requestById(id)
+		if err != nil {
+			return model.ErrorProvider[Model](err)
+		}
+		return model.Map(setItemId(id))(requests.Provider[RestModel, Model](l, ctx)(req, Extract))
+	}
+}
+
+func setItemId(id uint32) model.Transformer[Model, Model] {
+	return func(m Model) (Model, error) {
+		m.itemId = id
+		return m, nil
+	}
+}
+
+// GetById retrieves item information from game data, caching it for the tenant's version.
+func GetById(l logrus.FieldLogger, ctx context.Context) func(id uint32) (Model, error) {
+	return func(id uint32) (Model, error) {
+		return cache.Get(ctx, id, byIdModelProvider(l, ctx)(id))
+	}
+}
@@ -0,0 +1,39 @@
+package information
+
+import (
+	"atlas-character/rest"
+	"errors"
+	"fmt"
+	"github.com/Chronicle20/atlas-rest/requests"
+	"os"
+)
+
+const (
+	consumableById = "consumables/%d"
+	setupById      = "setups/%d"
+	etcById        = "etcs/%d"
+	cashById       = "cash/%d"
+)
+
+var ErrUnknownItemType = errors.New("unknown item type")
+
+func getBaseRequest() string {
+	return os.Getenv("GAME_DATA_SERVICE_URL")
+}
+
+func requestById(id uint32) (requests.Request[RestModel], error) {
+	var resource string
+	switch id / 1000000 {
+	case 2:
+		resource = consumableById
+	case 3:
+		resource = setupById
+	case 4:
+		resource = etcById
+	case 5:
+		resource = cashById
+	default:
+		return nil, ErrUnknownItemType
+	}
+	return rest.MakeGetRequest[RestModel](fmt.Sprintf(getBaseRequest()+resource, id)), nil
+}
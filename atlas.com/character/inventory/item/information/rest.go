@@ -0,0 +1,32 @@
+package information
+
+import "strconv"
+
+type RestModel struct {
+	Id      uint32 `json:"-"`
+	SlotMax uint32 `json:"slotMax"`
+}
+
+func (r RestModel) GetName() string {
+	return "items"
+}
+
+func (r RestModel) GetID() string {
+	return strconv.Itoa(int(r.Id))
+}
+
+func (r *RestModel) SetID(strId string) error {
+	id, err := strconv.Atoi(strId)
+	if err != nil {
+		return err
+	}
+	r.Id = uint32(id)
+	return nil
+}
+
+func Extract(m RestModel) (Model, error) {
+	return Model{
+		itemId:  m.Id,
+		slotMax: m.SlotMax,
+	}, nil
+}
@@ -15,7 +15,7 @@ func ByInventoryProvider(db *gorm.DB) func(ctx context.Context) func(inventoryId
 	return func(ctx context.Context) func(inventoryId uint32) model.Provider[[]Model] {
 		return func(inventoryId uint32) model.Provider[[]Model] {
 			t := tenant.MustFromContext(ctx)
-			return entityModelMapper(entityByInventory(t.Id())(inventoryId)(db))(model.ParallelMap())
+			return entityModelMapper(entityByInventory(t.Id())(inventoryId)(db.WithContext(ctx)))(model.ParallelMap())
 		}
 	}
 }
@@ -47,7 +47,7 @@ func BySlotProvider(db *gorm.DB) func(ctx context.Context) func(inventoryId uint
 		return func(inventoryId uint32) func(slot int16) model.Provider[Model] {
 			return func(slot int16) model.Provider[Model] {
 				t := tenant.MustFromContext(ctx)
-				return model.Map[entity, Model](makeModel)(getBySlot(t.Id(), inventoryId, slot)(db))
+				return model.Map[entity, Model](makeModel)(getBySlot(t.Id(), inventoryId, slot)(db.WithContext(ctx)))
 			}
 		}
 	}
@@ -65,7 +65,7 @@ func GetById(db *gorm.DB) func(ctx context.Context) func(id uint32) (Model, erro
 	return func(ctx context.Context) func(id uint32) (Model, error) {
 		return func(id uint32) (Model, error) {
 			t := tenant.MustFromContext(ctx)
-			return model.Map[entity, Model](makeModel)(getById(t.Id(), id)(db))()
+			return model.Map[entity, Model](makeModel)(getById(t.Id(), id)(db.WithContext(ctx)))()
 		}
 	}
 }
@@ -85,7 +85,7 @@ func ByItemIdProvider(db *gorm.DB) func(ctx context.Context) func(inventoryId ui
 		return func(inventoryId uint32) func(itemId uint32) model.Provider[[]Model] {
 			return func(itemId uint32) model.Provider[[]Model] {
 				t := tenant.MustFromContext(ctx)
-				return entityModelMapper(getForCharacter(t.Id(), inventoryId, itemId)(db))(model.ParallelMap())
+				return entityModelMapper(getForCharacter(t.Id(), inventoryId, itemId)(db.WithContext(ctx)))(model.ParallelMap())
 			}
 		}
 	}
@@ -102,7 +102,8 @@ func GetByItemId(db *gorm.DB) func(ctx context.Context) func(inventoryId uint32,
 func UpdateSlot(db *gorm.DB) func(ctx context.Context) func(id uint32, slot int16) error {
 	return func(ctx context.Context) func(id uint32, slot int16) error {
 		return func(id uint32, slot int16) error {
-			return updateSlot(db, id, slot)
+			t := tenant.MustFromContext(ctx)
+			return updateSlot(db.WithContext(ctx), t.Id(), id, slot)
 		}
 	}
 }
@@ -114,27 +115,34 @@ func UpdateQuantity(db *gorm.DB) func(ctx context.Context) func(id uint32, quant
 			if err != nil {
 				return err
 			}
-			return updateQuantity(db, i.Id(), quantity)
+			t := tenant.MustFromContext(ctx)
+			return updateQuantity(db.WithContext(ctx), t.Id(), i.Id(), quantity)
 		}
 	}
 }
 
-func CreateItem(db *gorm.DB) func(ctx context.Context) asset.CharacterAssetCreator {
-	return func(ctx context.Context) asset.CharacterAssetCreator {
-		return func(characterId uint32) asset.InventoryAssetCreator {
-			return func(inventoryId uint32, inventoryType int8) asset.ItemCreator {
-				return func(itemId uint32) asset.Creator {
-					return func(quantity uint32) model.Provider[asset.Asset] {
-						t := tenant.MustFromContext(ctx)
-						slot, err := slottable.GetNextFreeSlot(model.SliceMap(ToSlottable)(ByInventoryProvider(db)(ctx)(inventoryId))(model.ParallelMap()))
-						if err != nil {
-							return model.ErrorProvider[asset.Asset](err)
-						}
-						i, err := createItem(db, t.Id(), inventoryId, itemId, quantity, slot)
-						if err != nil {
-							return model.ErrorProvider[asset.Asset](err)
+func CreateItem(db *gorm.DB) func(ctx context.Context) func(capacityProvider slottable.CapacityProvider) asset.CharacterAssetCreator {
+	return func(ctx context.Context) func(capacityProvider slottable.CapacityProvider) asset.CharacterAssetCreator {
+		return func(capacityProvider slottable.CapacityProvider) asset.CharacterAssetCreator {
+			return func(characterId uint32) asset.InventoryAssetCreator {
+				return func(inventoryId uint32, inventoryType int8) asset.ItemCreator {
+					return func(itemId uint32) asset.Creator {
+						return func(quantity uint32) model.Provider[asset.Asset] {
+							t := tenant.MustFromContext(ctx)
+							capacity, err := capacityProvider(db)(ctx)(inventoryId)()
+							if err != nil {
+								return model.ErrorProvider[asset.Asset](err)
+							}
+							slot, err := slottable.GetNextFreeSlot(model.SliceMap(ToSlottable)(ByInventoryProvider(db)(ctx)(inventoryId))(model.ParallelMap()), inventoryId, capacity)
+							if err != nil {
+								return model.ErrorProvider[asset.Asset](err)
+							}
+							i, err := createItem(db.WithContext(ctx), t.Id(), inventoryId, itemId, quantity, slot)
+							if err != nil {
+								return model.ErrorProvider[asset.Asset](err)
+							}
+							return model.FixedProvider[asset.Asset](i)
 						}
-						return model.FixedProvider[asset.Asset](i)
 					}
 				}
 			}
@@ -142,6 +150,18 @@ func CreateItem(db *gorm.DB) func(ctx context.Context) asset.CharacterAssetCreat
 	}
 }
 
+// CreateItemInSlot creates a stack of the item in a specific slot, which the caller must know to be free.
+func CreateItemInSlot(db *gorm.DB) func(ctx context.Context) func(inventoryId uint32) func(itemId uint32, quantity uint32, slot int16) (Model, error) {
+	return func(ctx context.Context) func(inventoryId uint32) func(itemId uint32, quantity uint32, slot int16) (Model, error) {
+		return func(inventoryId uint32) func(itemId uint32, quantity uint32, slot int16) (Model, error) {
+			return func(itemId uint32, quantity uint32, slot int16) (Model, error) {
+				t := tenant.MustFromContext(ctx)
+				return createItem(db.WithContext(ctx), t.Id(), inventoryId, itemId, quantity, slot)
+			}
+		}
+	}
+}
+
 func ToAsset(m Model) (asset.Asset, error) {
 	return m, nil
 }
@@ -150,9 +170,12 @@ func ToSlottable(m Model) (asset.Slottable, error) {
 	return m, nil
 }
 
-func RemoveItem(db *gorm.DB) func(characterId uint32, id uint32) error {
-	return func(characterId uint32, id uint32) error {
-		return remove(db, characterId, id)
+func RemoveItem(db *gorm.DB) func(ctx context.Context) func(inventoryId uint32, id uint32) error {
+	return func(ctx context.Context) func(inventoryId uint32, id uint32) error {
+		return func(inventoryId uint32, id uint32) error {
+			t := tenant.MustFromContext(ctx)
+			return remove(db.WithContext(ctx), t.Id(), inventoryId, id)
+		}
 	}
 }
 
@@ -160,7 +183,7 @@ func DeleteById(db *gorm.DB) func(ctx context.Context) model.Operator[uint32] {
 	return func(ctx context.Context) model.Operator[uint32] {
 		return func(id uint32) error {
 			t := tenant.MustFromContext(ctx)
-			return deleteById(db, t.Id(), id)
+			return deleteById(db.WithContext(ctx), t.Id(), id)
 		}
 	}
 }
@@ -5,12 +5,29 @@ const (
 	EnvCommandTopicUnequipItem = "COMMAND_TOPIC_UNEQUIP_ITEM"
 	EnvCommandTopicMoveItem    = "COMMAND_TOPIC_MOVE_ITEM"
 	EnvCommandTopicDropItem    = "COMMAND_TOPIC_DROP_ITEM"
+	EnvCommandTopicPickupItem  = "COMMAND_TOPIC_PICKUP_ITEM"
+	EnvCommandTopicExpand      = "COMMAND_TOPIC_EXPAND_INVENTORY"
+	EnvCommandTopicConsumeItem = "COMMAND_TOPIC_CONSUME_ITEM"
+	EnvCommandTopicSplitItem   = "COMMAND_TOPIC_SPLIT_ITEM"
+	EnvCommandTopicSort        = "COMMAND_TOPIC_SORT_INVENTORY"
+	EnvCommandTopicGather      = "COMMAND_TOPIC_GATHER_INVENTORY"
 	EnvEventInventoryChanged   = "EVENT_TOPIC_INVENTORY_CHANGED"
+	EnvEventTopicDrop          = "EVENT_TOPIC_CHARACTER_DROP"
+	EventDropTypeDropped       = "DROPPED"
 
-	ChangedTypeAdd    = "INVENTORY_CHANGED_TYPE_ADD"
-	ChangedTypeUpdate = "INVENTORY_CHANGED_TYPE_UPDATE"
-	ChangedTypeRemove = "INVENTORY_CHANGED_TYPE_REMOVE"
-	ChangedTypeMove   = "INVENTORY_CHANGED_TYPE_MOVE"
+	ChangedTypeAdd      = "INVENTORY_CHANGED_TYPE_ADD"
+	ChangedTypeUpdate   = "INVENTORY_CHANGED_TYPE_UPDATE"
+	ChangedTypeRemove   = "INVENTORY_CHANGED_TYPE_REMOVE"
+	ChangedTypeMove     = "INVENTORY_CHANGED_TYPE_MOVE"
+	ChangedTypeCapacity = "INVENTORY_CAPACITY_CHANGED"
+
+	ChangedTypeEquipRejected   = "INVENTORY_CHANGED_TYPE_EQUIP_REJECTED"
+	EquipRejectedReasonUnknown = "UNKNOWN"
+
+	ChangedTypePickupRejected           = "INVENTORY_CHANGED_TYPE_PICKUP_REJECTED"
+	PickupRejectedReasonInventoryFull   = "INVENTORY_FULL"
+	PickupRejectedReasonEquipmentExists = "EQUIPMENT_EXISTS"
+	PickupRejectedReasonUnknown         = "UNKNOWN"
 )
 
 type equipItemCommand struct {
@@ -32,13 +49,55 @@ type moveItemCommand struct {
 	Destination   int16  `json:"destination"`
 }
 
+// pickupItemCommand returns a dropped item to a character. ReferenceId identifies the statistics a dropped equipable
+// already has, and is 0 for anything else.
+type pickupItemCommand struct {
+	CharacterId uint32 `json:"characterId"`
+	ItemId      uint32 `json:"itemId"`
+	Quantity    uint32 `json:"quantity"`
+	ReferenceId uint32 `json:"referenceId"`
+}
+
+type splitItemCommand struct {
+	CharacterId   uint32 `json:"characterId"`
+	InventoryType byte   `json:"inventoryType"`
+	Source        int16  `json:"source"`
+	Destination   int16  `json:"destination"`
+	Quantity      uint32 `json:"quantity"`
+}
+
 type dropItemCommand struct {
+	WorldId       byte   `json:"worldId"`
+	ChannelId     byte   `json:"channelId"`
+	MapId         uint32 `json:"mapId"`
 	CharacterId   uint32 `json:"characterId"`
 	InventoryType byte   `json:"inventoryType"`
 	Source        int16  `json:"source"`
 	Quantity      int16  `json:"quantity"`
 }
 
+type consumeItemCommand struct {
+	CharacterId   uint32 `json:"characterId"`
+	InventoryType byte   `json:"inventoryType"`
+	Source        int16  `json:"source"`
+	Quantity      uint32 `json:"quantity"`
+}
+
+type sortInventoryCommand struct {
+	CharacterId   uint32 `json:"characterId"`
+	InventoryType byte   `json:"inventoryType"`
+}
+
+type gatherInventoryCommand struct {
+	CharacterId   uint32 `json:"characterId"`
+	InventoryType byte   `json:"inventoryType"`
+}
+
+type expandInventoryCommand struct {
+	CharacterId   uint32 `json:"characterId"`
+	InventoryType byte   `json:"inventoryType"`
+}
+
 type inventoryChangedEvent[M any] struct {
 	CharacterId uint32 `json:"characterId"`
 	Slot        int16  `json:"slot"`
@@ -65,3 +124,65 @@ type inventoryChangedItemMoveBody struct {
 type inventoryChangedItemRemoveBody struct {
 	ItemId uint32 `json:"itemId"`
 }
+
+// inventoryChangedEquipRejectedBody describes why the item in the event slot could not be equipped. Reason is a
+// requirement the character does not meet, in which case Required and Actual are given, or UNKNOWN.
+type inventoryChangedEquipRejectedBody struct {
+	ItemId   uint32 `json:"itemId"`
+	Reason   string `json:"reason"`
+	Required uint32 `json:"required"`
+	Actual   uint32 `json:"actual"`
+}
+
+// inventoryChangedPickupRejectedBody describes an item which could not be picked up, so that it may be returned to
+// where it was dropped.
+type inventoryChangedPickupRejectedBody struct {
+	ItemId      uint32 `json:"itemId"`
+	Quantity    uint32 `json:"quantity"`
+	ReferenceId uint32 `json:"referenceId"`
+	Reason      string `json:"reason"`
+}
+
+type inventoryChangedCapacityBody struct {
+	InventoryType byte   `json:"inventoryType"`
+	Capacity      uint32 `json:"capacity"`
+}
+
+type dropEvent[E any] struct {
+	WorldId     byte   `json:"worldId"`
+	ChannelId   byte   `json:"channelId"`
+	MapId       uint32 `json:"mapId"`
+	CharacterId uint32 `json:"characterId"`
+	Type        string `json:"type"`
+	Body        E      `json:"body"`
+}
+
+// dropEventDroppedBody describes a dropped item. ReferenceId and Statistics are only given for equipment, and
+// Statistics may be absent if they could not be retrieved.
+type dropEventDroppedBody struct {
+	ItemId      uint32                   `json:"itemId"`
+	Quantity    uint32                   `json:"quantity"`
+	X           int16                    `json:"x"`
+	Y           int16                    `json:"y"`
+	ReferenceId uint32                   `json:"referenceId"`
+	Statistics  *dropEventStatisticsBody `json:"statistics,omitempty"`
+}
+
+type dropEventStatisticsBody struct {
+	Strength      uint16 `json:"strength"`
+	Dexterity     uint16 `json:"dexterity"`
+	Intelligence  uint16 `json:"intelligence"`
+	Luck          uint16 `json:"luck"`
+	HP            uint16 `json:"hp"`
+	MP            uint16 `json:"mp"`
+	WeaponAttack  uint16 `json:"weaponAttack"`
+	MagicAttack   uint16 `json:"magicAttack"`
+	WeaponDefense uint16 `json:"weaponDefense"`
+	MagicDefense  uint16 `json:"magicDefense"`
+	Accuracy      uint16 `json:"accuracy"`
+	Avoidability  uint16 `json:"avoidability"`
+	Hands         uint16 `json:"hands"`
+	Speed         uint16 `json:"speed"`
+	Jump          uint16 `json:"jump"`
+	Slots         uint16 `json:"slots"`
+}
@@ -7,7 +7,11 @@ import (
 	"atlas-character/equipment"
 	slot2 "atlas-character/equipment/slot"
 	"atlas-character/inventory/item"
+	"atlas-character/inventory/item/information"
 	"atlas-character/kafka/producer"
+	"atlas-character/rest"
+	"atlas-character/skill"
+	"atlas-character/slottable"
 	"context"
 	"errors"
 	"github.com/Chronicle20/atlas-model/model"
@@ -16,6 +20,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 	"math"
+	"sort"
 )
 
 func ByCharacterIdProvider(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(characterId uint32) model.Provider[Model] {
@@ -103,7 +108,7 @@ func Create(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) fu
 				tenant := tenant.MustFromContext(ctx)
 				err := db.Transaction(func(tx *gorm.DB) error {
 					for _, t := range Types {
-						_, err := create(db, tenant.Id(), characterId, int8(t), defaultCapacity)
+						_, err := create(tx, tenant.Id(), characterId, int8(t), defaultCapacity)
 						if err != nil {
 							l.WithError(err).Errorf("Unable to create inventory [%d] for character [%d].", t, characterId)
 							return err
@@ -121,69 +126,163 @@ func Create(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) fu
 	}
 }
 
+// DefaultCapacity is the number of slots a newly created inventory has.
+const DefaultCapacity = uint32(24)
+
+// CreateType creates a single inventory of the given type for the character.
+func CreateType(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(characterId uint32, inventoryType Type, capacity uint32) error {
+	return func(db *gorm.DB) func(ctx context.Context) func(characterId uint32, inventoryType Type, capacity uint32) error {
+		return func(ctx context.Context) func(characterId uint32, inventoryType Type, capacity uint32) error {
+			return func(characterId uint32, inventoryType Type, capacity uint32) error {
+				t := tenant.MustFromContext(ctx)
+				_, err := create(db.WithContext(ctx), t.Id(), characterId, int8(inventoryType), capacity)
+				if err != nil {
+					l.WithError(err).Errorf("Unable to create inventory [%d] for character [%d].", inventoryType, characterId)
+				}
+				return err
+			}
+		}
+	}
+}
+
 func CreateItem(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
 	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
 		return func(ctx context.Context) func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
 			return func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
-				return func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
+				return CreateItemWithStatistics(l)(db)(ctx)(eventProducer)(ItemSlotMaxProvider(l)(ctx))(statistics2.Create(l)(ctx))
+			}
+		}
+	}
+}
 
-					expectedInventoryType := math.Floor(float64(itemId) / 1000000)
-					if expectedInventoryType != float64(inventoryType) {
-						l.Errorf("Provided inventoryType [%d] does not match expected one [%d] for itemId [%d].", inventoryType, uint32(expectedInventoryType), itemId)
-						return errors.New("invalid inventory type")
+// CreateItemWithStatistics creates an item as CreateItem does, stacking items up to the slot max slotMaxLookup gives and
+// generating the statistics of equipment with statCreator.
+func CreateItemWithStatistics(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(statCreator statistics2.CreatorProvider) func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(statCreator statistics2.CreatorProvider) func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(statCreator statistics2.CreatorProvider) func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
+			return func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(statCreator statistics2.CreatorProvider) func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
+				return func(slotMaxLookup SlotMaxLookup) func(statCreator statistics2.CreatorProvider) func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
+					return func(statCreator statistics2.CreatorProvider) func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
+						return addItem(l)(db)(ctx)(eventProducer)(slotMaxLookup)(statCreator)
 					}
+				}
+			}
+		}
+	}
+}
 
-					if quantity == 0 {
-						quantity = 1
+// Pickup returns a previously dropped item to the character's inventory, stacking items up to the slot max
+// slotMaxLookup gives. Equipment keeps the statistics statCreator provides, which for a dropped item are those it
+// already had.
+func Pickup(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(statCreator statistics2.CreatorProvider) func(characterId uint32, itemId uint32, quantity uint32, referenceId uint32) error {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(statCreator statistics2.CreatorProvider) func(characterId uint32, itemId uint32, quantity uint32, referenceId uint32) error {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(statCreator statistics2.CreatorProvider) func(characterId uint32, itemId uint32, quantity uint32, referenceId uint32) error {
+			return func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(statCreator statistics2.CreatorProvider) func(characterId uint32, itemId uint32, quantity uint32, referenceId uint32) error {
+				return func(slotMaxLookup SlotMaxLookup) func(statCreator statistics2.CreatorProvider) func(characterId uint32, itemId uint32, quantity uint32, referenceId uint32) error {
+					return func(statCreator statistics2.CreatorProvider) func(characterId uint32, itemId uint32, quantity uint32, referenceId uint32) error {
+						return func(characterId uint32, itemId uint32, quantity uint32, referenceId uint32) error {
+							l.Debugf("Character [%d] picking up [%d] item [%d].", characterId, quantity, itemId)
+							if referenceId != 0 {
+								statCreator = unreferenced(ctx)(referenceId)(statCreator)
+							}
+							return addItem(l)(db)(ctx)(eventProducer)(slotMaxLookup)(statCreator)(characterId, Type(itemId/1000000), itemId, quantity)
+						}
 					}
+				}
+			}
+		}
+	}
+}
 
-					l.Debugf("Creating [%d] item [%d] for character [%d] in inventory [%d].", quantity, itemId, characterId, inventoryType)
-					invLock := GetLockRegistry().GetById(characterId, inventoryType)
-					invLock.Lock()
-					defer invLock.Unlock()
-
-					var events = model.FixedProvider[[]kafka.Message]([]kafka.Message{})
-					err := db.Transaction(func(tx *gorm.DB) error {
-						invId, err := GetInventoryIdByType(tx)(ctx)(characterId, inventoryType)()
-						if err != nil {
-							l.WithError(err).Errorf("Unable to locate inventory [%d] for character [%d].", inventoryType, characterId)
-							return err
-						}
+// ErrEquipmentExists is returned when picking up equipment whose statistics an equipable of the tenant already
+// references, such as a drop which was already picked up.
+var ErrEquipmentExists = errors.New("equipment already exists")
+
+// unreferenced guards the creation of equipment from the existing statistics identified by referenceId, failing with
+// ErrEquipmentExists should an equipable already reference them. The check is made within the creating transaction.
+func unreferenced(ctx context.Context) func(referenceId uint32) func(statCreator statistics2.CreatorProvider) statistics2.CreatorProvider {
+	return func(referenceId uint32) func(statCreator statistics2.CreatorProvider) statistics2.CreatorProvider {
+		return func(statCreator statistics2.CreatorProvider) statistics2.CreatorProvider {
+			return func(db *gorm.DB) statistics2.Creator {
+				return func(itemId uint32) model.Provider[statistics2.Model] {
+					exists, err := equipable.ReferenceExists(db)(ctx)(referenceId)
+					if err != nil {
+						return model.ErrorProvider[statistics2.Model](err)
+					}
+					if exists {
+						return model.ErrorProvider[statistics2.Model](ErrEquipmentExists)
+					}
+					return statCreator(db)(itemId)
+				}
+			}
+		}
+	}
+}
 
-						iap := inventoryItemAddProvider(characterId)(itemId)
-						iup := inventoryItemUpdateProvider(characterId)(itemId)
-						var eap model.Provider[[]asset.Asset]
-						var smp SlotMaxProvider
-						var nac asset.Creator
-						var aqu asset.QuantityUpdater
+func addItem(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(statCreator statistics2.CreatorProvider) func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(statCreator statistics2.CreatorProvider) func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(statCreator statistics2.CreatorProvider) func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
+			return func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(statCreator statistics2.CreatorProvider) func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
+				return func(slotMaxLookup SlotMaxLookup) func(statCreator statistics2.CreatorProvider) func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
+					return func(statCreator statistics2.CreatorProvider) func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
+						return func(characterId uint32, inventoryType Type, itemId uint32, quantity uint32) error {
+
+							expectedInventoryType := math.Floor(float64(itemId) / 1000000)
+							if expectedInventoryType != float64(inventoryType) {
+								l.Errorf("Provided inventoryType [%d] does not match expected one [%d] for itemId [%d].", inventoryType, uint32(expectedInventoryType), itemId)
+								return errors.New("invalid inventory type")
+							}
 
-						if inventoryType == TypeValueEquip {
-							eap = asset.NoOpSliceProvider
-							smp = OfOneSlotMaxProvider
-							nac = equipable.CreateItem(l)(tx)(ctx)(statistics2.Create(l)(ctx))(characterId)(invId, int8(inventoryType))(itemId)
-							aqu = asset.NoOpQuantityUpdater
-						} else {
-							eap = item.AssetByItemIdProvider(tx)(ctx)(invId)(itemId)
-							smp = func() (uint32, error) {
-								// TODO properly look this up.
-								return 200, nil
+							if quantity == 0 {
+								quantity = 1
 							}
-							nac = item.CreateItem(tx)(ctx)(characterId)(invId, int8(inventoryType))(itemId)
-							aqu = item.UpdateQuantity(tx)(ctx)
-						}
 
-						res, err := CreateAsset(l)(eap, smp, nac, aqu, iap, iup, quantity)()
-						if err != nil {
-							l.WithError(err).Errorf("Unable to create [%d] equipable [%d] for character [%d].", quantity, itemId, characterId)
-							return err
+							l.Debugf("Creating [%d] item [%d] for character [%d] in inventory [%d].", quantity, itemId, characterId, inventoryType)
+							invLock := GetLockRegistry().GetById(characterId, inventoryType)
+							invLock.Lock()
+							defer invLock.Unlock()
+
+							var events = model.FixedProvider[[]kafka.Message]([]kafka.Message{})
+							err := db.Transaction(func(tx *gorm.DB) error {
+								invId, err := GetInventoryIdByType(tx)(ctx)(characterId, inventoryType)()
+								if err != nil {
+									l.WithError(err).Errorf("Unable to locate inventory [%d] for character [%d].", inventoryType, characterId)
+									return err
+								}
+
+								iap := inventoryItemAddProvider(characterId)(itemId)
+								iup := inventoryItemUpdateProvider(characterId)(itemId)
+								var eap model.Provider[[]asset.Asset]
+								var smp SlotMaxProvider
+								var nac asset.Creator
+								var aqu asset.QuantityUpdater
+
+								if inventoryType == TypeValueEquip {
+									eap = asset.NoOpSliceProvider
+									smp = OfOneSlotMaxProvider
+									nac = equipable.CreateItem(l)(tx)(ctx)(GetCapacity)(statCreator(tx))(characterId)(invId, int8(inventoryType))(itemId)
+									aqu = asset.NoOpQuantityUpdater
+								} else {
+									eap = item.AssetByItemIdProvider(tx)(ctx)(invId)(itemId)
+									smp = slotMaxLookup(characterId)(itemId)
+									nac = item.CreateItem(tx)(ctx)(GetCapacity)(characterId)(invId, int8(inventoryType))(itemId)
+									aqu = item.UpdateQuantity(tx)(ctx)
+								}
+
+								res, err := CreateAsset(l)(eap, smp, nac, aqu, iap, iup, quantity)()
+								if err != nil {
+									l.WithError(err).Errorf("Unable to create [%d] equipable [%d] for character [%d].", quantity, itemId, characterId)
+									return err
+								}
+								events = model.MergeSliceProvider(events, model.FixedProvider(res))
+								return err
+							})
+							if err != nil {
+								return err
+							}
+							return eventProducer(EnvEventInventoryChanged)(events)
 						}
-						events = model.MergeSliceProvider(events, model.FixedProvider(res))
-						return err
-					})
-					if err != nil {
-						return err
 					}
-					return eventProducer(EnvEventInventoryChanged)(events)
 				}
 			}
 		}
@@ -203,12 +302,150 @@ func GetInventoryIdByType(db *gorm.DB) func(ctx context.Context) func(characterI
 	}
 }
 
+// GetCapacity provides the capacity of an inventory. It is the slottable.CapacityProvider given to the packages which
+// place assets in inventories.
+func GetCapacity(db *gorm.DB) func(ctx context.Context) func(inventoryId uint32) model.Provider[uint32] {
+	return func(ctx context.Context) func(inventoryId uint32) model.Provider[uint32] {
+		return func(inventoryId uint32) model.Provider[uint32] {
+			t := tenant.MustFromContext(ctx)
+			e, err := getById(t.Id(), inventoryId)(db)()
+			if err != nil {
+				return model.ErrorProvider[uint32](err)
+			}
+			return model.FixedProvider(e.Capacity)
+		}
+	}
+}
+
+// expansionIncrement is the number of slots in a row of the inventory, by which inventories are expanded.
+const expansionIncrement = uint32(4)
+
+// DefaultMaxCapacity is the most slots an inventory may be expanded to when the tenant configures no maximum.
+const DefaultMaxCapacity = uint32(96)
+
+var ErrMaxCapacity = errors.New("inventory at maximum capacity")
+
+// Expand grows an inventory by a row of slots, up to maxCapacity.
+func Expand(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type, maxCapacity uint32) error {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type, maxCapacity uint32) error {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type, maxCapacity uint32) error {
+			return func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type, maxCapacity uint32) error {
+				return func(characterId uint32, inventoryType Type, maxCapacity uint32) error {
+					l.Debugf("Expanding inventory [%d] for character [%d].", inventoryType, characterId)
+					invLock := GetLockRegistry().GetById(characterId, inventoryType)
+					invLock.Lock()
+					defer invLock.Unlock()
+
+					t := tenant.MustFromContext(ctx)
+					var capacity uint32
+					err := db.Transaction(func(tx *gorm.DB) error {
+						e, err := get(t.Id(), characterId, inventoryType)(tx)()
+						if err != nil {
+							l.WithError(err).Errorf("Unable to locate inventory [%d] for character [%d].", inventoryType, characterId)
+							return err
+						}
+						capacity = e.Capacity + expansionIncrement
+						if capacity > maxCapacity {
+							l.Debugf("Inventory [%d] for character [%d] is already at [%d] of a maximum [%d] slots.", inventoryType, characterId, e.Capacity, maxCapacity)
+							return ErrMaxCapacity
+						}
+						return updateCapacity(tx, t.Id(), e.ID, capacity)
+					})
+					if err != nil {
+						return err
+					}
+					return eventProducer(EnvEventInventoryChanged)(inventoryCapacityChangedProvider(characterId, inventoryType, capacity))
+				}
+			}
+		}
+	}
+}
+
 type SlotMaxProvider model.Provider[uint32]
 
 func OfOneSlotMaxProvider() (uint32, error) {
 	return 1, nil
 }
 
+// defaultSlotMax is the slot max of items which game data gives none for.
+const defaultSlotMax = uint32(100)
+
+// ItemSlotMaxProvider provides how many of an item fit in a single slot for the character, including skill bonuses,
+// from game data and the skills of the character.
+func ItemSlotMaxProvider(l logrus.FieldLogger) func(ctx context.Context) func(characterId uint32) func(itemId uint32) SlotMaxProvider {
+	return func(ctx context.Context) func(characterId uint32) func(itemId uint32) SlotMaxProvider {
+		return SlotMaxLookupOf(l)(information.GetById(l, ctx), skill.GetById(l, ctx))
+	}
+}
+
+// ItemLookup retrieves an item from game data.
+type ItemLookup func(itemId uint32) (information.Model, error)
+
+// SkillLookup retrieves a skill of a character.
+type SkillLookup func(characterId uint32, skillId uint32) (skill.Model, error)
+
+// SlotMaxLookupOf provides how many of an item fit in a single slot for a character, including skill bonuses. Failing
+// to retrieve the item or a skill is an error, rather than a reason to assume the default or no bonus. A skill the
+// character does not have grants no bonus.
+func SlotMaxLookupOf(l logrus.FieldLogger) func(itemLookup ItemLookup, skillLookup SkillLookup) SlotMaxLookup {
+	return func(itemLookup ItemLookup, skillLookup SkillLookup) SlotMaxLookup {
+		return func(characterId uint32) func(itemId uint32) SlotMaxProvider {
+			return func(itemId uint32) SlotMaxProvider {
+				return func() (uint32, error) {
+					i, err := itemLookup(itemId)
+					if err != nil {
+						l.WithError(err).Errorf("Unable to retrieve slot max for item [%d].", itemId)
+						return 0, err
+					}
+					slotMax := defaultSlotMax
+					if i.SlotMax() > 0 {
+						slotMax = i.SlotMax()
+					}
+					bonus, err := slotMaxBonus(l)(skillLookup)(characterId)(itemId)
+					if err != nil {
+						return 0, err
+					}
+					return slotMax + bonus, nil
+				}
+			}
+		}
+	}
+}
+
+// slotMaxBonusSkills are the mastery skills which allow 10 more of a rechargeable item per slot for each skill level.
+func slotMaxBonusSkills(itemId uint32) []uint32 {
+	if item.IsThrowingStar(itemId) {
+		return []uint32{skill.AssassinClawMastery, skill.NightWalkerClawMastery}
+	}
+	if item.IsBullet(itemId) {
+		return []uint32{skill.GunslingerGunMastery}
+	}
+	return nil
+}
+
+func slotMaxBonus(l logrus.FieldLogger) func(skillLookup SkillLookup) func(characterId uint32) func(itemId uint32) (uint32, error) {
+	return func(skillLookup SkillLookup) func(characterId uint32) func(itemId uint32) (uint32, error) {
+		return func(characterId uint32) func(itemId uint32) (uint32, error) {
+			return func(itemId uint32) (uint32, error) {
+				bonus := uint32(0)
+				for _, skillId := range slotMaxBonusSkills(itemId) {
+					s, err := skillLookup(characterId, skillId)
+					if rest.IsNotFound(err) {
+						continue
+					}
+					if err != nil {
+						l.WithError(err).Errorf("Unable to retrieve skill [%d] for character [%d].", skillId, characterId)
+						return 0, err
+					}
+					bonus += uint32(s.Level()) * 10
+				}
+				return bonus, nil
+			}
+		}
+	}
+}
+
+// CreateAsset tops up existing stacks before creating new ones. Any failure is returned so the caller's transaction discards the partial grant.
 func CreateAsset(l logrus.FieldLogger) func(existingAssetProvider model.Provider[[]asset.Asset], slotMaxProvider SlotMaxProvider, newAssetCreator asset.Creator, assetQuantityUpdater asset.QuantityUpdater, addEventProvider ItemAddProvider, updateEventProvider ItemUpdateProvider, quantity uint32) model.Provider[[]kafka.Message] {
 	return func(existingAssetProvider model.Provider[[]asset.Asset], slotMaxProvider SlotMaxProvider, newAssetCreator asset.Creator, assetQuantityUpdater asset.QuantityUpdater, addEventProvider ItemAddProvider, updateEventProvider ItemUpdateProvider, quantity uint32) model.Provider[[]kafka.Message] {
 		runningQuantity := quantity
@@ -239,9 +476,9 @@ func CreateAsset(l logrus.FieldLogger) func(existingAssetProvider model.Provider
 						err = assetQuantityUpdater(i.Id(), newQuantity)
 						if err != nil {
 							l.WithError(err).Errorf("Updating the quantity of item [%d] to value [%d].", i.Id(), newQuantity)
-						} else {
-							result = model.MergeSliceProvider(result, updateEventProvider(newQuantity, i.Slot()))
+							return model.ErrorProvider[[]kafka.Message](err)
 						}
+						result = model.MergeSliceProvider(result, updateEventProvider(newQuantity, i.Slot()))
 					}
 					index++
 				} else {
@@ -263,95 +500,131 @@ func CreateAsset(l logrus.FieldLogger) func(existingAssetProvider model.Provider
 	}
 }
 
-func EquipItemForCharacter(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(freeSlotProvider func(db *gorm.DB) func(uint32) model.Provider[int16]) func(eventProducer producer.Provider) func(characterId uint32) func(source int16) func(destinationProvider equipment.DestinationProvider) {
-	return func(db *gorm.DB) func(ctx context.Context) func(freeSlotProvider func(db *gorm.DB) func(uint32) model.Provider[int16]) func(eventProducer producer.Provider) func(characterId uint32) func(source int16) func(destinationProvider equipment.DestinationProvider) {
-		return func(ctx context.Context) func(freeSlotProvider func(db *gorm.DB) func(uint32) model.Provider[int16]) func(eventProducer producer.Provider) func(characterId uint32) func(source int16) func(destinationProvider equipment.DestinationProvider) {
-			return func(freeSlotProvider func(db *gorm.DB) func(uint32) model.Provider[int16]) func(eventProducer producer.Provider) func(characterId uint32) func(source int16) func(destinationProvider equipment.DestinationProvider) {
-				return func(eventProducer producer.Provider) func(characterId uint32) func(source int16) func(destinationProvider equipment.DestinationProvider) {
-					return func(characterId uint32) func(source int16) func(destinationProvider equipment.DestinationProvider) {
-						characterInventoryMoveProvider := inventoryItemMoveProvider(characterId)
-						return func(source int16) func(destinationProvider equipment.DestinationProvider) {
-							return func(destinationProvider equipment.DestinationProvider) {
-								var e equipable.Model
-								var err error
-
-								l.Debugf("Received request to equip item at [%d] for character [%d].", source, characterId)
-								invLock := GetLockRegistry().GetById(characterId, TypeValueEquip)
-								invLock.Lock()
-								defer invLock.Unlock()
-
-								var events = model.FixedProvider[[]kafka.Message]([]kafka.Message{})
-
-								err = db.Transaction(func(tx *gorm.DB) error {
-									inSlotProvider := equipable.AssetBySlotProvider(tx)(ctx)(characterId)
-									slotUpdater := equipable.UpdateSlot(tx)(ctx)
-
-									e, err = equipable.GetBySlot(tx)(ctx)(characterId, source)
-									if err != nil {
-										l.WithError(err).Errorf("Unable to retrieve equipment in slot [%d].", source)
-										return err
-									}
-
-									l.Debugf("Equipment [%d] is item [%d] for character [%d].", e.Id(), e.ItemId(), characterId)
-
-									actualDestination, err := destinationProvider(e.ItemId())()
-									if err != nil {
-										l.WithError(err).Errorf("Unable to determine actual destination for item being equipped.")
-										return err
-									}
-
-									l.Debugf("Equipment [%d] to be equipped in slot [%d] for character [%d].", e.Id(), actualDestination, characterId)
-
-									l.Debugf("Attempting to move item that is currently occupying the destination to a temporary position.")
-									resp, _ := moveFromSlotToSlot(l)(inSlotProvider(actualDestination), temporarySlotProvider, slotUpdater, noOpInventoryItemMoveProvider)()
-									events = model.MergeSliceProvider(events, model.FixedProvider(resp))
+func EquipItemForCharacter(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(freeSlotProvider func(db *gorm.DB) func(uint32) model.Provider[int16]) func(twoHandedProvider equipment.TwoHandedProvider) func(eventProducer producer.Provider) func(characterId uint32) func(source int16) func(destinationProvider equipment.DestinationProvider, validators ...equipment.ValidatorProvider) error {
+	return func(db *gorm.DB) func(ctx context.Context) func(freeSlotProvider func(db *gorm.DB) func(uint32) model.Provider[int16]) func(twoHandedProvider equipment.TwoHandedProvider) func(eventProducer producer.Provider) func(characterId uint32) func(source int16) func(destinationProvider equipment.DestinationProvider, validators ...equipment.ValidatorProvider) error {
+		return func(ctx context.Context) func(freeSlotProvider func(db *gorm.DB) func(uint32) model.Provider[int16]) func(twoHandedProvider equipment.TwoHandedProvider) func(eventProducer producer.Provider) func(characterId uint32) func(source int16) func(destinationProvider equipment.DestinationProvider, validators ...equipment.ValidatorProvider) error {
+			return func(freeSlotProvider func(db *gorm.DB) func(uint32) model.Provider[int16]) func(twoHandedProvider equipment.TwoHandedProvider) func(eventProducer producer.Provider) func(characterId uint32) func(source int16) func(destinationProvider equipment.DestinationProvider, validators ...equipment.ValidatorProvider) error {
+				return func(twoHandedProvider equipment.TwoHandedProvider) func(eventProducer producer.Provider) func(characterId uint32) func(source int16) func(destinationProvider equipment.DestinationProvider, validators ...equipment.ValidatorProvider) error {
+					return func(eventProducer producer.Provider) func(characterId uint32) func(source int16) func(destinationProvider equipment.DestinationProvider, validators ...equipment.ValidatorProvider) error {
+						return func(characterId uint32) func(source int16) func(destinationProvider equipment.DestinationProvider, validators ...equipment.ValidatorProvider) error {
+							characterInventoryMoveProvider := inventoryItemMoveProvider(characterId)
+							return func(source int16) func(destinationProvider equipment.DestinationProvider, validators ...equipment.ValidatorProvider) error {
+								return func(destinationProvider equipment.DestinationProvider, validators ...equipment.ValidatorProvider) error {
+									var e equipable.Model
+									var err error
+
+									l.Debugf("Received request to equip item at [%d] for character [%d].", source, characterId)
+									invLock := GetLockRegistry().GetById(characterId, TypeValueEquip)
+									invLock.Lock()
+									defer invLock.Unlock()
+
+									var events = model.FixedProvider[[]kafka.Message]([]kafka.Message{})
+
+									err = db.Transaction(func(tx *gorm.DB) error {
+										inSlotProvider := equipable.AssetBySlotProvider(tx)(ctx)(characterId)
+										slotUpdater := equipable.UpdateSlot(tx)(ctx)
+
+										e, err = equipable.GetBySlot(tx)(ctx)(characterId, source)
+										if err != nil {
+											l.WithError(err).Errorf("Unable to retrieve equipment in slot [%d].", source)
+											return err
+										}
 
-									l.Debugf("Attempting to move item that is being equipped to its final destination.")
-									resp, _ = moveFromSlotToSlot(l)(inSlotProvider(source), model.FixedProvider(actualDestination), slotUpdater, characterInventoryMoveProvider(source))()
-									events = model.MergeSliceProvider(events, model.FixedProvider(resp))
+										l.Debugf("Equipment [%d] is item [%d] for character [%d].", e.Id(), e.ItemId(), characterId)
 
-									l.Debugf("Attempting to move item that is in the temporary position to where the item that was just equipped was.")
-									resp, _ = moveFromSlotToSlot(l)(inSlotProvider(temporarySlot()), model.FixedProvider(source), slotUpdater, noOpInventoryItemMoveProvider)()
-									events = model.MergeSliceProvider(events, model.FixedProvider(resp))
+										occupied := func(s int16) bool {
+											_, err := inSlotProvider(s)()
+											return err == nil
+										}
+										actualDestination, err := destinationProvider(e.ItemId(), occupied)()
+										if err != nil {
+											l.WithError(err).Errorf("Unable to determine actual destination for item being equipped.")
+											return err
+										}
 
-									l.Debugf("Now verifying other inventory operations that may be necessary.")
+										for _, v := range validators {
+											err = v(tx)(e.ItemId(), actualDestination)
+											if err != nil {
+												l.WithError(err).Debugf("Character [%d] cannot equip item [%d].", characterId, e.ItemId())
+												return err
+											}
+										}
 
-									invId, err := GetInventoryIdByType(tx)(ctx)(characterId, TypeValueEquip)()
-									if err != nil {
-										l.WithError(err).Errorf("Unable to locate inventory [%d] for character [%d].", TypeValueEquip, characterId)
-										return err
-									}
-									nextFreeSlotProvider := freeSlotProvider(tx)(invId)
+										l.Debugf("Equipment [%d] to be equipped in slot [%d] for character [%d].", e.Id(), actualDestination, characterId)
 
-									if e.ItemId()/10000 == 105 {
-										l.Debugf("Item is an overall, we also need to unequip the bottom.")
-										resp, err = moveFromSlotToSlot(l)(inSlotProvider(int16(slot2.PositionBottom)), nextFreeSlotProvider, slotUpdater, characterInventoryMoveProvider(int16(slot2.PositionBottom)))()
+										resp, err := swapSlots(l)(inSlotProvider, slotUpdater, characterInventoryMoveProvider)(source, actualDestination)()
 										if err != nil {
-											l.WithError(err).Errorf("Unable to move bottom out of its slot.")
+											l.WithError(err).Errorf("Unable to move item from [%d] to [%d] for character [%d].", source, actualDestination, characterId)
 											return err
 										}
 										events = model.MergeSliceProvider(events, model.FixedProvider(resp))
-									}
-									if actualDestination == int16(slot2.PositionBottom) {
-										l.Debugf("Item is a bottom, need to unequip an overall if its in the top slot.")
-										ip := model.Map(IsOverall)(inSlotProvider(int16(slot2.PositionOverall)))
-										resp, err = moveFromSlotToSlot(l)(ip, nextFreeSlotProvider, slotUpdater, characterInventoryMoveProvider(int16(slot2.PositionOverall)))()
-										if err != nil && !errors.Is(err, notOverall) {
-											l.WithError(err).Errorf("Unable to move overall out of its slot.")
+
+										l.Debugf("Now verifying other inventory operations that may be necessary.")
+
+										invId, err := GetInventoryIdByType(tx)(ctx)(characterId, TypeValueEquip)()
+										if err != nil {
+											l.WithError(err).Errorf("Unable to locate inventory [%d] for character [%d].", TypeValueEquip, characterId)
 											return err
 										}
-										events = model.MergeSliceProvider(events, model.FixedProvider(resp))
+										nextFreeSlotProvider := freeSlotProvider(tx)(invId)
+
+										if e.ItemId()/10000 == 105 {
+											l.Debugf("Item is an overall, we also need to unequip the bottom.")
+											resp, err = moveFromSlotToSlot(l)(inSlotProvider(int16(slot2.PositionBottom)), nextFreeSlotProvider, slotUpdater, characterInventoryMoveProvider(int16(slot2.PositionBottom)))()
+											if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+												l.WithError(err).Errorf("Unable to move bottom out of its slot.")
+												return err
+											}
+											events = model.MergeSliceProvider(events, model.FixedProvider(resp))
+										}
+										if actualDestination == int16(slot2.PositionBottom) {
+											l.Debugf("Item is a bottom, need to unequip an overall if its in the top slot.")
+											ip := model.Map(IsOverall)(inSlotProvider(int16(slot2.PositionOverall)))
+											resp, err = moveFromSlotToSlot(l)(ip, nextFreeSlotProvider, slotUpdater, characterInventoryMoveProvider(int16(slot2.PositionOverall)))()
+											if err != nil && !errors.Is(err, notOverall) && !errors.Is(err, gorm.ErrRecordNotFound) {
+												l.WithError(err).Errorf("Unable to move overall out of its slot.")
+												return err
+											}
+											events = model.MergeSliceProvider(events, model.FixedProvider(resp))
+										}
+										if actualDestination == int16(slot2.PositionWeapon) {
+											th, err := twoHandedProvider(e.ItemId())()
+											if err != nil {
+												l.WithError(err).Errorf("Unable to determine if weapon [%d] is two-handed.", e.ItemId())
+												return err
+											}
+											if th {
+												l.Debugf("Item is a two-handed weapon, need to unequip the shield.")
+												resp, err = moveFromSlotToSlot(l)(inSlotProvider(int16(slot2.PositionShield)), nextFreeSlotProvider, slotUpdater, characterInventoryMoveProvider(int16(slot2.PositionShield)))()
+												if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+													l.WithError(err).Errorf("Unable to move shield out of its slot.")
+													return err
+												}
+												events = model.MergeSliceProvider(events, model.FixedProvider(resp))
+											}
+										}
+										if actualDestination == int16(slot2.PositionShield) {
+											l.Debugf("Item is a shield, need to unequip a two-handed weapon if one is in the weapon slot.")
+											ip := model.Map(IsTwoHanded(twoHandedProvider))(inSlotProvider(int16(slot2.PositionWeapon)))
+											resp, err = moveFromSlotToSlot(l)(ip, nextFreeSlotProvider, slotUpdater, characterInventoryMoveProvider(int16(slot2.PositionWeapon)))()
+											if err != nil && !errors.Is(err, notTwoHanded) && !errors.Is(err, gorm.ErrRecordNotFound) {
+												l.WithError(err).Errorf("Unable to move two-handed weapon out of its slot.")
+												return err
+											}
+											events = model.MergeSliceProvider(events, model.FixedProvider(resp))
+										}
+										return nil
+									})
+									if err != nil {
+										l.WithError(err).Errorf("Unable to complete the equipment of item [%d] for character [%d].", e.Id(), characterId)
+										return err
 									}
-									return nil
-								})
-								if err != nil {
-									l.WithError(err).Errorf("Unable to complete the equipment of item [%d] for character [%d].", e.Id(), characterId)
-									return
-								}
 
-								err = eventProducer(EnvEventInventoryChanged)(events)
-								if err != nil {
-									l.WithError(err).Errorf("Unable to convey inventory modifications to character [%d].", characterId)
+									err = eventProducer(EnvEventInventoryChanged)(events)
+									if err != nil {
+										l.WithError(err).Errorf("Unable to convey inventory modifications to character [%d].", characterId)
+									}
+									return err
 								}
 							}
 						}
@@ -362,7 +635,27 @@ func EquipItemForCharacter(l logrus.FieldLogger) func(db *gorm.DB) func(ctx cont
 	}
 }
 
+// ErrInventoryFull is matched by the InventoryFullError returned when an inventory has no free slot within its capacity.
+var ErrInventoryFull = slottable.ErrInventoryFull
+
+// InventoryFullError carries the inventory which had no free slot, and its capacity.
+type InventoryFullError = slottable.InventoryFullError
+
 var notOverall = errors.New("not an overall")
+var notTwoHanded = errors.New("not a two-handed weapon")
+
+func IsTwoHanded(twoHandedProvider equipment.TwoHandedProvider) func(m asset.Asset) (asset.Asset, error) {
+	return func(m asset.Asset) (asset.Asset, error) {
+		th, err := twoHandedProvider(m.ItemId())()
+		if err != nil {
+			return nil, err
+		}
+		if th {
+			return m, nil
+		}
+		return nil, notTwoHanded
+	}
+}
 
 func IsOverall(m asset.Asset) (asset.Asset, error) {
 	if m.ItemId()/10000 == 105 {
@@ -371,6 +664,33 @@ func IsOverall(m asset.Asset) (asset.Asset, error) {
 	return nil, notOverall
 }
 
+// swapSlots moves the asset in source to destination. An asset occupying destination is parked in the temporary slot
+// and then moved to source. Empty slots are skipped; any other failure is returned so the swap can be rolled back.
+func swapSlots(l logrus.FieldLogger) func(inSlotProvider func(slot int16) model.Provider[asset.Asset], slotUpdater func(id uint32, slot int16) error, moveEventProvider func(oldSlot int16) func(itemId uint32) func(slot int16) model.Provider[[]kafka.Message]) func(source int16, destination int16) model.Provider[[]kafka.Message] {
+	return func(inSlotProvider func(slot int16) model.Provider[asset.Asset], slotUpdater func(id uint32, slot int16) error, moveEventProvider func(oldSlot int16) func(itemId uint32) func(slot int16) model.Provider[[]kafka.Message]) func(source int16, destination int16) model.Provider[[]kafka.Message] {
+		return func(source int16, destination int16) model.Provider[[]kafka.Message] {
+			l.Debugf("Attempting to move item that is currently occupying the destination to a temporary position.")
+			parked, err := moveFromSlotToSlot(l)(inSlotProvider(destination), temporarySlotProvider, slotUpdater, noOpInventoryItemMoveProvider)()
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return model.ErrorProvider[[]kafka.Message](err)
+			}
+
+			l.Debugf("Attempting to move item that is being moved to its final destination.")
+			moved, err := moveFromSlotToSlot(l)(inSlotProvider(source), model.FixedProvider(destination), slotUpdater, moveEventProvider(source))()
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return model.ErrorProvider[[]kafka.Message](err)
+			}
+
+			l.Debugf("Attempting to move item that is in the temporary position to where the moved item was.")
+			returned, err := moveFromSlotToSlot(l)(inSlotProvider(temporarySlot()), model.FixedProvider(source), slotUpdater, noOpInventoryItemMoveProvider)()
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return model.ErrorProvider[[]kafka.Message](err)
+			}
+			return model.FixedProvider(append(append(parked, moved...), returned...))
+		}
+	}
+}
+
 func moveFromSlotToSlot(l logrus.FieldLogger) func(modelProvider model.Provider[asset.Asset], newSlotProvider model.Provider[int16], slotUpdater func(id uint32, slot int16) error, moveEventProvider func(itemId uint32) func(slot int16) model.Provider[[]kafka.Message]) model.Provider[[]kafka.Message] {
 	return func(modelProvider model.Provider[asset.Asset], newSlotProvider model.Provider[int16], slotUpdater func(id uint32, slot int16) error, moveEventProvider func(itemId uint32) func(slot int16) model.Provider[[]kafka.Message]) model.Provider[[]kafka.Message] {
 		m, err := modelProvider()
@@ -486,15 +806,20 @@ func DeleteItemInventory(l logrus.FieldLogger) func(db *gorm.DB) func(ctx contex
 
 type AssetMover func(characterId uint32) func(source int16) func(destination int16) error
 
-func Move(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(inventoryType byte) AssetMover {
-	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(inventoryType byte) AssetMover {
-		return func(ctx context.Context) func(eventProducer producer.Provider) func(inventoryType byte) AssetMover {
-			return func(eventProducer producer.Provider) func(inventoryType byte) AssetMover {
-				return func(inventoryType byte) AssetMover {
-					if inventoryType == 1 {
-						return moveEquip(l)(db)(ctx)(eventProducer)
-					} else {
-						return moveItem(l)(db)(ctx)(eventProducer)(inventoryType)
+// SlotMaxLookup provides how many of an item fit in a single slot for a character.
+type SlotMaxLookup func(characterId uint32) func(itemId uint32) SlotMaxProvider
+
+func Move(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(inventoryType byte) AssetMover {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(inventoryType byte) AssetMover {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(inventoryType byte) AssetMover {
+			return func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(inventoryType byte) AssetMover {
+				return func(slotMaxLookup SlotMaxLookup) func(inventoryType byte) AssetMover {
+					return func(inventoryType byte) AssetMover {
+						if inventoryType == 1 {
+							return moveEquip(l)(db)(ctx)(eventProducer)
+						} else {
+							return moveItem(l)(db)(ctx)(eventProducer)(slotMaxLookup)(inventoryType)
+						}
 					}
 				}
 			}
@@ -502,56 +827,71 @@ func Move(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func
 	}
 }
 
-func moveItem(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(inventoryType byte) AssetMover {
-	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(inventoryType byte) AssetMover {
-		return func(ctx context.Context) func(eventProducer producer.Provider) func(inventoryType byte) AssetMover {
-			return func(eventProducer producer.Provider) func(inventoryType byte) AssetMover {
-				return func(inventoryType byte) AssetMover {
-					return func(characterId uint32) func(source int16) func(destination int16) error {
-						return func(source int16) func(destination int16) error {
-							return func(destination int16) error {
-								characterInventoryMoveProvider := inventoryItemMoveProvider(characterId)
-
-								l.Debugf("Received request to move item at [%d] to [%d] for character [%d].", source, destination, characterId)
-								invLock := GetLockRegistry().GetById(characterId, Type(inventoryType))
-								invLock.Lock()
-								defer invLock.Unlock()
+// moveItem moves the item in source to destination. When destination holds a stack of the same item, as much of source
+// as fits is merged into it, otherwise the two are swapped.
+func moveItem(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(inventoryType byte) AssetMover {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(inventoryType byte) AssetMover {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(inventoryType byte) AssetMover {
+			return func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(inventoryType byte) AssetMover {
+				return func(slotMaxLookup SlotMaxLookup) func(inventoryType byte) AssetMover {
+					return func(inventoryType byte) AssetMover {
+						return func(characterId uint32) func(source int16) func(destination int16) error {
+							return func(source int16) func(destination int16) error {
+								return func(destination int16) error {
+									characterInventoryMoveProvider := inventoryItemMoveProvider(characterId)
+
+									l.Debugf("Received request to move item at [%d] to [%d] for character [%d].", source, destination, characterId)
+									invLock := GetLockRegistry().GetById(characterId, Type(inventoryType))
+									invLock.Lock()
+									defer invLock.Unlock()
+
+									var events = model.FixedProvider[[]kafka.Message]([]kafka.Message{})
+									txErr := db.Transaction(func(tx *gorm.DB) error {
+										slotUpdater := item.UpdateSlot(tx)(ctx)
+
+										invId, err := GetInventoryIdByType(tx)(ctx)(characterId, Type(inventoryType))()
+										if err != nil {
+											l.WithError(err).Errorf("Unable to locate inventory [%d] for character [%d].", inventoryType, characterId)
+											return err
+										}
 
-								// TODO need to combine quantities if moving to the same item type.
+										src, srcErr := item.GetBySlot(tx)(ctx)(invId, source)
+										dst, dstErr := item.GetBySlot(tx)(ctx)(invId, destination)
+										if srcErr == nil && dstErr == nil && source != destination && isMergeable(src, dst) {
+											slotMax, err := slotMaxLookup(characterId)(dst.ItemId())()
+											if err != nil {
+												return err
+											}
+											if dst.Quantity() < slotMax {
+												resp, err := mergeItem(l)(tx)(ctx)(characterId)(src, dst, slotMax)()
+												if err != nil {
+													return err
+												}
+												events = model.MergeSliceProvider(events, model.FixedProvider(resp))
+												return nil
+											}
+										}
 
-								var events = model.FixedProvider[[]kafka.Message]([]kafka.Message{})
-								txErr := db.Transaction(func(tx *gorm.DB) error {
-									slotUpdater := item.UpdateSlot(tx)(ctx)
+										inSlotProvider := item.AssetBySlotProvider(tx)(ctx)(invId)
 
-									invId, err := GetInventoryIdByType(tx)(ctx)(characterId, Type(inventoryType))()
+										resp, err := swapSlots(l)(inSlotProvider, slotUpdater, characterInventoryMoveProvider)(source, destination)()
+										if err != nil {
+											l.WithError(err).Errorf("Unable to move item from [%d] to [%d] for character [%d].", source, destination, characterId)
+											return err
+										}
+										events = model.MergeSliceProvider(events, model.FixedProvider(resp))
+										return nil
+									})
+									if txErr != nil {
+										l.WithError(txErr).Errorf("Unable to complete moving item for character [%d].", characterId)
+										return txErr
+									}
+									err := eventProducer(EnvEventInventoryChanged)(events)
 									if err != nil {
-										l.WithError(err).Errorf("Unable to locate inventory [%d] for character [%d].", inventoryType, characterId)
-										return err
+										l.WithError(err).Errorf("Unable to convey inventory modifications to character [%d].", characterId)
 									}
-									inSlotProvider := item.AssetBySlotProvider(tx)(ctx)(invId)
-
-									l.Debugf("Attempting to move item that is currently occupying the destination to a temporary position.")
-									resp, _ := moveFromSlotToSlot(l)(inSlotProvider(destination), temporarySlotProvider, slotUpdater, noOpInventoryItemMoveProvider)()
-									events = model.MergeSliceProvider(events, model.FixedProvider(resp))
-
-									l.Debugf("Attempting to move item that is being moved to its final destination.")
-									resp, _ = moveFromSlotToSlot(l)(inSlotProvider(source), model.FixedProvider(destination), slotUpdater, characterInventoryMoveProvider(source))()
-									events = model.MergeSliceProvider(events, model.FixedProvider(resp))
-
-									l.Debugf("Attempting to move item that is in the temporary position to where the item that was just equipped was.")
-									resp, _ = moveFromSlotToSlot(l)(inSlotProvider(temporarySlot()), model.FixedProvider(source), slotUpdater, noOpInventoryItemMoveProvider)()
-									events = model.MergeSliceProvider(events, model.FixedProvider(resp))
-									return nil
-								})
-								if txErr != nil {
-									l.WithError(txErr).Errorf("Unable to complete moving item for character [%d].", characterId)
-									return txErr
-								}
-								err := eventProducer(EnvEventInventoryChanged)(events)
-								if err != nil {
-									l.WithError(err).Errorf("Unable to convey inventory modifications to character [%d].", characterId)
+									return err
 								}
-								return err
 							}
 						}
 					}
@@ -561,129 +901,345 @@ func moveItem(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context)
 	}
 }
 
-func temporarySlot() int16 {
-	return int16(math.MinInt16)
+// isMergeable determines whether two stacks may be combined. Rechargeable stacks are never combined.
+func isMergeable(src item.Model, dst item.Model) bool {
+	if src.ItemId() != dst.ItemId() {
+		return false
+	}
+	return !isRechargeable(src.ItemId())
 }
 
-var temporarySlotProvider = model.FixedProvider(temporarySlot())
+func isRechargeable(itemId uint32) bool {
+	return item.IsThrowingStar(itemId) || item.IsBullet(itemId)
+}
 
-func moveEquip(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) AssetMover {
-	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) AssetMover {
-		return func(ctx context.Context) func(eventProducer producer.Provider) AssetMover {
-			return func(eventProducer producer.Provider) AssetMover {
-				return func(characterId uint32) func(source int16) func(destination int16) error {
-					return func(source int16) func(destination int16) error {
-						return func(destination int16) error {
-							l.Debugf("Received request to move item at [%d] to [%d] for character [%d].", source, destination, characterId)
-							invLock := GetLockRegistry().GetById(characterId, TypeValueEquip)
-							invLock.Lock()
-							defer invLock.Unlock()
+// mergeItem moves as much of src into dst as the slot max allows, removing src if none of it remains.
+func mergeItem(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(characterId uint32) func(src item.Model, dst item.Model, slotMax uint32) model.Provider[[]kafka.Message] {
+	return func(db *gorm.DB) func(ctx context.Context) func(characterId uint32) func(src item.Model, dst item.Model, slotMax uint32) model.Provider[[]kafka.Message] {
+		return func(ctx context.Context) func(characterId uint32) func(src item.Model, dst item.Model, slotMax uint32) model.Provider[[]kafka.Message] {
+			return func(characterId uint32) func(src item.Model, dst item.Model, slotMax uint32) model.Provider[[]kafka.Message] {
+				return func(src item.Model, dst item.Model, slotMax uint32) model.Provider[[]kafka.Message] {
+					moved := uint32(math.Min(float64(src.Quantity()), float64(slotMax-dst.Quantity())))
+					l.Debugf("Merging [%d] of item [%d] from slot [%d] into slot [%d].", moved, src.ItemId(), src.Slot(), dst.Slot())
 
-							var events = model.FixedProvider[[]kafka.Message]([]kafka.Message{})
-							txErr := db.Transaction(func(tx *gorm.DB) error {
-								inSlotProvider := equipable.AssetBySlotProvider(tx)(ctx)(characterId)
-								slotUpdater := equipable.UpdateSlot(tx)(ctx)
-								characterInventoryMoveProvider := inventoryItemMoveProvider(characterId)
+					var events = model.FixedProvider[[]kafka.Message]([]kafka.Message{})
+					if moved == src.Quantity() {
+						err := item.DeleteById(db)(ctx)(src.Id())
+						if err != nil {
+							return model.ErrorProvider[[]kafka.Message](err)
+						}
+						events = model.MergeSliceProvider(events, inventoryItemRemoveProvider(characterId, src.ItemId(), src.Slot()))
+					} else {
+						err := item.UpdateQuantity(db)(ctx)(src.Id(), src.Quantity()-moved)
+						if err != nil {
+							return model.ErrorProvider[[]kafka.Message](err)
+						}
+						events = model.MergeSliceProvider(events, inventoryItemUpdateProvider(characterId)(src.ItemId())(src.Quantity()-moved, src.Slot()))
+					}
 
-								l.Debugf("Attempting to move item that is currently occupying the destination to a temporary position.")
-								resp, _ := moveFromSlotToSlot(l)(inSlotProvider(destination), temporarySlotProvider, slotUpdater, noOpInventoryItemMoveProvider)()
-								events = model.MergeSliceProvider(events, model.FixedProvider(resp))
+					err := item.UpdateQuantity(db)(ctx)(dst.Id(), dst.Quantity()+moved)
+					if err != nil {
+						return model.ErrorProvider[[]kafka.Message](err)
+					}
+					return model.MergeSliceProvider(events, inventoryItemUpdateProvider(characterId)(dst.ItemId())(dst.Quantity()+moved, dst.Slot()))
+				}
+			}
+		}
+	}
+}
 
-								l.Debugf("Attempting to move item that is being moved to its final destination.")
-								resp, _ = moveFromSlotToSlot(l)(inSlotProvider(source), model.FixedProvider(destination), slotUpdater, characterInventoryMoveProvider(source))()
-								events = model.MergeSliceProvider(events, model.FixedProvider(resp))
+var ErrSlotOccupied = errors.New("slot occupied")
+var ErrInvalidSlot = errors.New("invalid slot")
+var ErrNotStackable = errors.New("inventory does not hold stackable items")
+
+// Split moves quantity of the stack in source into the empty destination slot.
+func Split(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type, source int16, destination int16, quantity uint32) error {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type, source int16, destination int16, quantity uint32) error {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type, source int16, destination int16, quantity uint32) error {
+			return func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type, source int16, destination int16, quantity uint32) error {
+				return func(characterId uint32, inventoryType Type, source int16, destination int16, quantity uint32) error {
+					if inventoryType == TypeValueEquip {
+						return ErrNotStackable
+					}
 
-								l.Debugf("Attempting to move item that is in the temporary position to where the item that was just equipped was.")
-								resp, _ = moveFromSlotToSlot(l)(inSlotProvider(temporarySlot()), model.FixedProvider(source), slotUpdater, noOpInventoryItemMoveProvider)()
-								events = model.MergeSliceProvider(events, model.FixedProvider(resp))
-								return nil
-							})
-							if txErr != nil {
-								l.WithError(txErr).Errorf("Unable to complete moving item for character [%d].", characterId)
-								return txErr
-							}
-							err := eventProducer(EnvEventInventoryChanged)(events)
-							if err != nil {
-								l.WithError(err).Errorf("Unable to convey inventory modifications to character [%d].", characterId)
-							}
+					l.Debugf("Received request to split [%d] of item at [%d] to [%d] for character [%d].", quantity, source, destination, characterId)
+					invLock := GetLockRegistry().GetById(characterId, inventoryType)
+					invLock.Lock()
+					defer invLock.Unlock()
+
+					var events = model.FixedProvider[[]kafka.Message]([]kafka.Message{})
+					txErr := db.Transaction(func(tx *gorm.DB) error {
+						invId, err := GetInventoryIdByType(tx)(ctx)(characterId, inventoryType)()
+						if err != nil {
+							l.WithError(err).Errorf("Unable to locate inventory [%d] for character [%d].", inventoryType, characterId)
+							return err
+						}
+						capacity, err := GetCapacity(tx)(ctx)(invId)()
+						if err != nil {
+							return err
+						}
+						if destination <= 0 || (capacity > 0 && uint32(destination) > capacity) {
+							return ErrInvalidSlot
+						}
+
+						src, err := item.GetBySlot(tx)(ctx)(invId, source)
+						if err != nil {
+							l.WithError(err).Errorf("Unable to retrieve item in slot [%d].", source)
 							return err
 						}
+						if quantity == 0 || quantity >= src.Quantity() {
+							return ErrInsufficientQuantity
+						}
+						_, err = item.GetBySlot(tx)(ctx)(invId, destination)
+						if err == nil {
+							return ErrSlotOccupied
+						}
+						if !errors.Is(err, gorm.ErrRecordNotFound) {
+							return err
+						}
+
+						err = item.UpdateQuantity(tx)(ctx)(src.Id(), src.Quantity()-quantity)
+						if err != nil {
+							return err
+						}
+						events = model.MergeSliceProvider(events, inventoryItemUpdateProvider(characterId)(src.ItemId())(src.Quantity()-quantity, src.Slot()))
+
+						_, err = item.CreateItemInSlot(tx)(ctx)(invId)(src.ItemId(), quantity, destination)
+						if err != nil {
+							return err
+						}
+						events = model.MergeSliceProvider(events, inventoryItemAddProvider(characterId)(src.ItemId())(quantity, destination))
+						return nil
+					})
+					if txErr != nil {
+						l.WithError(txErr).Errorf("Unable to complete splitting item for character [%d].", characterId)
+						return txErr
 					}
+					return eventProducer(EnvEventInventoryChanged)(events)
 				}
 			}
 		}
 	}
 }
 
-type AssetDropper func(characterId uint32) func(source int16) func(quantity int16) error
+// Gather compacts the items of an inventory into its lowest slots, keeping their order.
+func Gather(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type) error {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type) error {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type) error {
+			return func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type) error {
+				return func(characterId uint32, inventoryType Type) error {
+					if inventoryType == TypeValueEquip {
+						return ErrNotStackable
+					}
 
-// Drop drops an asset from the designated inventory.
-func Drop(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(inventoryType byte) AssetDropper {
-	return func(db *gorm.DB) func(ctx context.Context) func(inventoryType byte) AssetDropper {
-		return func(ctx context.Context) func(inventoryType byte) AssetDropper {
-			return func(inventoryType byte) AssetDropper {
-				if inventoryType == 1 {
-					return dropEquip(l)(db)(ctx)
-				} else {
-					return dropItem(l)(db)(ctx)(inventoryType)
+					l.Debugf("Received request to gather inventory [%d] for character [%d].", inventoryType, characterId)
+					invLock := GetLockRegistry().GetById(characterId, inventoryType)
+					invLock.Lock()
+					defer invLock.Unlock()
+
+					var events = model.FixedProvider[[]kafka.Message]([]kafka.Message{})
+					txErr := db.Transaction(func(tx *gorm.DB) error {
+						invId, err := GetInventoryIdByType(tx)(ctx)(characterId, inventoryType)()
+						if err != nil {
+							l.WithError(err).Errorf("Unable to locate inventory [%d] for character [%d].", inventoryType, characterId)
+							return err
+						}
+						is, err := item.GetByInventory(tx)(ctx)(invId)
+						if err != nil {
+							return err
+						}
+						sort.Slice(is, func(i, j int) bool {
+							return is[i].Slot() < is[j].Slot()
+						})
+
+						// Each item only ever moves down into a slot already vacated, so no temporary slot is needed.
+						slot := int16(1)
+						for _, i := range is {
+							if i.Slot() <= 0 {
+								continue
+							}
+							if i.Slot() != slot {
+								err = item.UpdateSlot(tx)(ctx)(i.Id(), slot)
+								if err != nil {
+									return err
+								}
+								events = model.MergeSliceProvider(events, inventoryItemMoveProvider(characterId)(i.Slot())(i.ItemId())(slot))
+							}
+							slot++
+						}
+						return nil
+					})
+					if txErr != nil {
+						l.WithError(txErr).Errorf("Unable to complete gathering inventory for character [%d].", characterId)
+						return txErr
+					}
+					return eventProducer(EnvEventInventoryChanged)(events)
 				}
 			}
 		}
 	}
 }
 
-func dropItem(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(inventoryType byte) AssetDropper {
-	return func(db *gorm.DB) func(ctx context.Context) func(inventoryType byte) AssetDropper {
-		return func(ctx context.Context) func(inventoryType byte) AssetDropper {
-			return func(inventoryType byte) AssetDropper {
-				return func(characterId uint32) func(source int16) func(quantity int16) error {
-					return func(source int16) func(quantity int16) error {
-						return func(quantity int16) error {
-							l.Debugf("Received request to drop item at [%d] for character [%d].", source, characterId)
-							invLock := GetLockRegistry().GetById(characterId, Type(inventoryType))
-							invLock.Lock()
-							defer invLock.Unlock()
+// Sort orders the items of an inventory by item id from the lowest slot, merging partial stacks of the same item. The
+// result is conveyed as quantity updates of merged stacks and removals of stacks emptied by a merge, followed by the
+// moves which put the remaining stacks in order. Moves are made from the lowest slot, each swapping the stack moved with
+// any stack in its destination, as the client applies them.
+func Sort(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(characterId uint32, inventoryType Type) error {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(characterId uint32, inventoryType Type) error {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(characterId uint32, inventoryType Type) error {
+			return func(eventProducer producer.Provider) func(slotMaxLookup SlotMaxLookup) func(characterId uint32, inventoryType Type) error {
+				return func(slotMaxLookup SlotMaxLookup) func(characterId uint32, inventoryType Type) error {
+					return func(characterId uint32, inventoryType Type) error {
+						if inventoryType == TypeValueEquip {
+							return ErrNotStackable
+						}
 
-							var events = model.FixedProvider[[]kafka.Message]([]kafka.Message{})
-							txErr := db.Transaction(func(tx *gorm.DB) error {
-								invId, err := GetInventoryIdByType(tx)(ctx)(characterId, Type(inventoryType))()
+						l.Debugf("Received request to sort inventory [%d] for character [%d].", inventoryType, characterId)
+						invLock := GetLockRegistry().GetById(characterId, inventoryType)
+						invLock.Lock()
+						defer invLock.Unlock()
+
+						var events = model.FixedProvider[[]kafka.Message]([]kafka.Message{})
+						txErr := db.Transaction(func(tx *gorm.DB) error {
+							invId, err := GetInventoryIdByType(tx)(ctx)(characterId, inventoryType)()
+							if err != nil {
+								l.WithError(err).Errorf("Unable to locate inventory [%d] for character [%d].", inventoryType, characterId)
+								return err
+							}
+							is, err := item.GetByInventory(tx)(ctx)(invId)
+							if err != nil {
+								return err
+							}
+							sort.SliceStable(is, func(i, j int) bool {
+								if is[i].ItemId() != is[j].ItemId() {
+									return is[i].ItemId() < is[j].ItemId()
+								}
+								return is[i].Slot() < is[j].Slot()
+							})
+
+							quantities := make([]uint32, len(is))
+							for idx, i := range is {
+								quantities[idx] = i.Quantity()
+							}
+							for idx := 0; idx < len(is); idx++ {
+								if quantities[idx] == 0 || isRechargeable(is[idx].ItemId()) {
+									continue
+								}
+								slotMax, err := slotMaxLookup(characterId)(is[idx].ItemId())()
 								if err != nil {
-									l.WithError(err).Errorf("Unable to locate inventory [%d] for character [%d].", inventoryType, characterId)
 									return err
 								}
+								for next := idx + 1; next < len(is) && is[next].ItemId() == is[idx].ItemId() && quantities[idx] < slotMax; next++ {
+									moved := uint32(math.Min(float64(quantities[next]), float64(slotMax-quantities[idx])))
+									quantities[idx] += moved
+									quantities[next] -= moved
+								}
+							}
 
-								i, err := item.GetBySlot(tx)(ctx)(invId, source)
+							// occupant and at track the slots of the remaining stacks as the client sees them, between moves. A vacated
+							// slot is occupied by -1.
+							occupant := make(map[int16]int)
+							at := make([]int16, len(is))
+							for idx, i := range is {
+								if quantities[idx] == 0 {
+									events = model.MergeSliceProvider(events, inventoryItemRemoveProvider(characterId, i.ItemId(), i.Slot()))
+									err = item.DeleteById(tx)(ctx)(i.Id())
+								} else {
+									if quantities[idx] != i.Quantity() {
+										events = model.MergeSliceProvider(events, inventoryItemUpdateProvider(characterId)(i.ItemId())(quantities[idx], i.Slot()))
+									}
+									occupant[i.Slot()] = idx
+									at[idx] = i.Slot()
+									err = item.UpdateSlot(tx)(ctx)(i.Id(), temporarySlot()+int16(idx))
+								}
 								if err != nil {
-									l.WithError(err).Errorf("Unable to retrieve item in slot [%d].", source)
 									return err
 								}
+							}
 
-								initialQuantity := i.Quantity()
-
-								if initialQuantity <= uint32(quantity) {
-									err = item.DeleteById(tx)(ctx)(i.Id())
+							slot := int16(1)
+							for idx, i := range is {
+								if quantities[idx] == 0 {
+									continue
+								}
+								err = item.UpdateSlot(tx)(ctx)(i.Id(), slot)
+								if err != nil {
+									return err
+								}
+								if quantities[idx] != i.Quantity() {
+									err = item.UpdateQuantity(tx)(ctx)(i.Id(), quantities[idx])
 									if err != nil {
-										l.WithError(err).Errorf("Unable to drop item in slot [%d].", source)
 										return err
 									}
-									events = model.MergeSliceProvider(events, inventoryItemRemoveProvider(characterId, i.ItemId(), i.Slot()))
-									return nil
 								}
+								if from := at[idx]; from != slot {
+									events = model.MergeSliceProvider(events, inventoryItemMoveProvider(characterId)(from)(i.ItemId())(slot))
+									displaced, ok := occupant[slot]
+									if ok && displaced >= 0 {
+										at[displaced] = from
+									} else {
+										displaced = -1
+									}
+									occupant[from] = displaced
+									occupant[slot] = idx
+									at[idx] = slot
+								}
+								slot++
+							}
+							return nil
+						})
+						if txErr != nil {
+							l.WithError(txErr).Errorf("Unable to complete sorting inventory for character [%d].", characterId)
+							return txErr
+						}
+						return eventProducer(EnvEventInventoryChanged)(events)
+					}
+				}
+			}
+		}
+	}
+}
+
+// temporarySlot is an out of range slot an asset is parked in while two assets swap slots. Swaps move one asset at a
+// time, so at most one asset of an inventory occupies it and the unique (inventory, slot) constraint holds after every
+// statement without needing deferred constraints.
+func temporarySlot() int16 {
+	return int16(math.MinInt16)
+}
+
+var temporarySlotProvider = model.FixedProvider(temporarySlot())
+
+func moveEquip(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) AssetMover {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) AssetMover {
+		return func(ctx context.Context) func(eventProducer producer.Provider) AssetMover {
+			return func(eventProducer producer.Provider) AssetMover {
+				return func(characterId uint32) func(source int16) func(destination int16) error {
+					return func(source int16) func(destination int16) error {
+						return func(destination int16) error {
+							l.Debugf("Received request to move item at [%d] to [%d] for character [%d].", source, destination, characterId)
+							invLock := GetLockRegistry().GetById(characterId, TypeValueEquip)
+							invLock.Lock()
+							defer invLock.Unlock()
 
-								newQuantity := initialQuantity - uint32(quantity)
-								err = item.UpdateQuantity(tx)(ctx)(i.Id(), newQuantity)
+							var events = model.FixedProvider[[]kafka.Message]([]kafka.Message{})
+							txErr := db.Transaction(func(tx *gorm.DB) error {
+								inSlotProvider := equipable.AssetBySlotProvider(tx)(ctx)(characterId)
+								slotUpdater := equipable.UpdateSlot(tx)(ctx)
+								characterInventoryMoveProvider := inventoryItemMoveProvider(characterId)
+
+								resp, err := swapSlots(l)(inSlotProvider, slotUpdater, characterInventoryMoveProvider)(source, destination)()
 								if err != nil {
-									l.WithError(err).Errorf("Unable to drop [%d] item in slot [%d].", quantity, source)
+									l.WithError(err).Errorf("Unable to move item from [%d] to [%d] for character [%d].", source, destination, characterId)
 									return err
 								}
-								events = model.MergeSliceProvider(events, inventoryItemUpdateProvider(characterId)(i.ItemId())(newQuantity, i.Slot()))
+								events = model.MergeSliceProvider(events, model.FixedProvider(resp))
 								return nil
 							})
 							if txErr != nil {
-								l.WithError(txErr).Errorf("Unable to complete dropping item for character [%d].", characterId)
+								l.WithError(txErr).Errorf("Unable to complete moving item for character [%d].", characterId)
 								return txErr
 							}
-							err := producer.ProviderImpl(l)(ctx)(EnvEventInventoryChanged)(events)
+							err := eventProducer(EnvEventInventoryChanged)(events)
 							if err != nil {
 								l.WithError(err).Errorf("Unable to convey inventory modifications to character [%d].", characterId)
 							}
@@ -696,44 +1252,298 @@ func dropItem(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context)
 	}
 }
 
-func dropEquip(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) AssetDropper {
-	return func(db *gorm.DB) func(ctx context.Context) AssetDropper {
-		return func(ctx context.Context) AssetDropper {
-			return func(characterId uint32) func(source int16) func(quantity int16) error {
-				return func(source int16) func(quantity int16) error {
-					return func(quantity int16) error {
-						l.Debugf("Received request to drop item at [%d] for character [%d].", source, characterId)
-						invLock := GetLockRegistry().GetById(characterId, TypeValueEquip)
-						invLock.Lock()
-						defer invLock.Unlock()
+var ErrInsufficientQuantity = errors.New("insufficient quantity")
 
-						var events = model.FixedProvider[[]kafka.Message]([]kafka.Message{})
-						txErr := db.Transaction(func(tx *gorm.DB) error {
+// Consume uses up quantity of the asset in the source slot, removing it once none remain. Unlike Drop, nothing is
+// left behind in the world.
+func Consume(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type, source int16, quantity uint32) error {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type, source int16, quantity uint32) error {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type, source int16, quantity uint32) error {
+			return func(eventProducer producer.Provider) func(characterId uint32, inventoryType Type, source int16, quantity uint32) error {
+				return func(characterId uint32, inventoryType Type, source int16, quantity uint32) error {
+					if quantity == 0 {
+						quantity = 1
+					}
+					if inventoryType == TypeValueEquip && source <= 0 {
+						l.Errorf("Character [%d] attempted to consume equipment they are wearing in slot [%d].", characterId, source)
+						return ErrInvalidSlot
+					}
+
+					l.Debugf("Consuming [%d] of the item in slot [%d] of inventory [%d] for character [%d].", quantity, source, inventoryType, characterId)
+					invLock := GetLockRegistry().GetById(characterId, inventoryType)
+					invLock.Lock()
+					defer invLock.Unlock()
+
+					var events = model.FixedProvider[[]kafka.Message]([]kafka.Message{})
+					var consumed equipable.Model
+					txErr := db.Transaction(func(tx *gorm.DB) error {
+						if inventoryType == TypeValueEquip {
 							e, err := equipable.GetBySlot(tx)(ctx)(characterId, source)
 							if err != nil {
 								l.WithError(err).Errorf("Unable to retrieve equipment in slot [%d].", source)
 								return err
 							}
-							err = equipable.DropByReferenceId(l)(tx)(ctx)(e.ReferenceId())
+							if quantity > 1 {
+								return ErrInsufficientQuantity
+							}
+							err = equipable.RemoveByReferenceId(l)(tx)(ctx)(e.ReferenceId())
 							if err != nil {
-								l.WithError(err).Errorf("Unable to drop equipment in slot [%d].", source)
+								l.WithError(err).Errorf("Unable to consume equipment in slot [%d].", source)
 								return err
 							}
+							consumed = e
 							events = model.MergeSliceProvider(events, inventoryItemRemoveProvider(characterId, e.ItemId(), e.Slot()))
 							return nil
-						})
-						if txErr != nil {
-							l.WithError(txErr).Errorf("Unable to complete dropping item for character [%d].", characterId)
-							return txErr
 						}
-						err := producer.ProviderImpl(l)(ctx)(EnvEventInventoryChanged)(events)
+
+						invId, err := GetInventoryIdByType(tx)(ctx)(characterId, inventoryType)()
 						if err != nil {
-							l.WithError(err).Errorf("Unable to convey inventory modifications to character [%d].", characterId)
+							l.WithError(err).Errorf("Unable to locate inventory [%d] for character [%d].", inventoryType, characterId)
+							return err
 						}
-						return err
+						i, err := item.GetBySlot(tx)(ctx)(invId, source)
+						if err != nil {
+							l.WithError(err).Errorf("Unable to retrieve item in slot [%d].", source)
+							return err
+						}
+						if i.Quantity() < quantity {
+							l.Errorf("Unable to consume [%d] of item [%d] in slot [%d], only [%d] held.", quantity, i.ItemId(), source, i.Quantity())
+							return ErrInsufficientQuantity
+						}
+						if i.Quantity() == quantity {
+							err = item.DeleteById(tx)(ctx)(i.Id())
+							if err != nil {
+								l.WithError(err).Errorf("Unable to consume item in slot [%d].", source)
+								return err
+							}
+							events = model.MergeSliceProvider(events, inventoryItemRemoveProvider(characterId, i.ItemId(), i.Slot()))
+							return nil
+						}
+
+						newQuantity := i.Quantity() - quantity
+						err = item.UpdateQuantity(tx)(ctx)(i.Id(), newQuantity)
+						if err != nil {
+							l.WithError(err).Errorf("Unable to consume [%d] item in slot [%d].", quantity, source)
+							return err
+						}
+						events = model.MergeSliceProvider(events, inventoryItemUpdateProvider(characterId)(i.ItemId())(newQuantity, i.Slot()))
+						return nil
+					})
+					if txErr != nil {
+						l.WithError(txErr).Errorf("Unable to complete consuming item for character [%d].", characterId)
+						return txErr
 					}
+					if consumed.ReferenceId() != 0 {
+						// The equipment is gone, so statistics left behind by a failure are only unreferenced.
+						err := statistics2.Release(l, ctx)(consumed.ReferenceId())
+						if err != nil {
+							l.WithError(err).Errorf("Unable to delete statistics [%d] of equipment consumed by character [%d].", consumed.ReferenceId(), characterId)
+						}
+					}
+					return eventProducer(EnvEventInventoryChanged)(events)
 				}
 			}
 		}
 	}
 }
+
+type AssetDropper func(characterId uint32) func(source int16) func(quantity int16) error
+
+// Position is where a character stands on its map.
+type Position struct {
+	X int16
+	Y int16
+}
+
+// PositionProvider supplies the current position of a character.
+type PositionProvider func(characterId uint32) model.Provider[Position]
+
+// Field identifies the map instance a character is in.
+type Field struct {
+	WorldId   byte
+	ChannelId byte
+	MapId     uint32
+}
+
+// Drop drops an asset from the designated inventory, announcing what was dropped and where so that it may be spawned.
+func Drop(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(positionProvider PositionProvider) func(field Field) func(inventoryType byte) AssetDropper {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(positionProvider PositionProvider) func(field Field) func(inventoryType byte) AssetDropper {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(positionProvider PositionProvider) func(field Field) func(inventoryType byte) AssetDropper {
+			return func(eventProducer producer.Provider) func(positionProvider PositionProvider) func(field Field) func(inventoryType byte) AssetDropper {
+				return func(positionProvider PositionProvider) func(field Field) func(inventoryType byte) AssetDropper {
+					return func(field Field) func(inventoryType byte) AssetDropper {
+						return func(inventoryType byte) AssetDropper {
+							dep := droppedEventProvider(l)(db)(ctx)(positionProvider)(field)
+							if inventoryType == 1 {
+								return dropEquip(l)(db)(ctx)(eventProducer)(dep)
+							} else {
+								return dropItem(l)(db)(ctx)(eventProducer)(dep)(inventoryType)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// droppedEventProvider produces the DROPPED event of an asset, including the statistics of equipment.
+func droppedEventProvider(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(positionProvider PositionProvider) func(field Field) func(characterId uint32, itemId uint32, quantity uint32, referenceId uint32) model.Provider[[]kafka.Message] {
+	return func(db *gorm.DB) func(ctx context.Context) func(positionProvider PositionProvider) func(field Field) func(characterId uint32, itemId uint32, quantity uint32, referenceId uint32) model.Provider[[]kafka.Message] {
+		return func(ctx context.Context) func(positionProvider PositionProvider) func(field Field) func(characterId uint32, itemId uint32, quantity uint32, referenceId uint32) model.Provider[[]kafka.Message] {
+			return func(positionProvider PositionProvider) func(field Field) func(characterId uint32, itemId uint32, quantity uint32, referenceId uint32) model.Provider[[]kafka.Message] {
+				return func(field Field) func(characterId uint32, itemId uint32, quantity uint32, referenceId uint32) model.Provider[[]kafka.Message] {
+					return func(characterId uint32, itemId uint32, quantity uint32, referenceId uint32) model.Provider[[]kafka.Message] {
+						p, err := positionProvider(characterId)()
+						if err != nil {
+							return model.ErrorProvider[[]kafka.Message](err)
+						}
+						var sm *statistics2.Model
+						if referenceId != 0 {
+							es, err := statistics2.GetById(l, db, ctx)(referenceId)
+							if err != nil {
+								l.WithError(err).Warnf("Unable to retrieve statistics [%d] of dropped equipment [%d]. The drop will only carry the reference.", referenceId, itemId)
+							} else {
+								sm = &es
+							}
+						}
+						return itemDroppedEventProvider(characterId, field, p, itemId, quantity, referenceId, sm)
+					}
+				}
+			}
+		}
+	}
+}
+
+type droppedEventFunc func(characterId uint32, itemId uint32, quantity uint32, referenceId uint32) model.Provider[[]kafka.Message]
+
+func dropItem(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(droppedEventProvider droppedEventFunc) func(inventoryType byte) AssetDropper {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(droppedEventProvider droppedEventFunc) func(inventoryType byte) AssetDropper {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(droppedEventProvider droppedEventFunc) func(inventoryType byte) AssetDropper {
+			return func(eventProducer producer.Provider) func(droppedEventProvider droppedEventFunc) func(inventoryType byte) AssetDropper {
+				return func(droppedEventProvider droppedEventFunc) func(inventoryType byte) AssetDropper {
+					return func(inventoryType byte) AssetDropper {
+						return func(characterId uint32) func(source int16) func(quantity int16) error {
+							return func(source int16) func(quantity int16) error {
+								return func(quantity int16) error {
+									l.Debugf("Received request to drop item at [%d] for character [%d].", source, characterId)
+									invLock := GetLockRegistry().GetById(characterId, Type(inventoryType))
+									invLock.Lock()
+									defer invLock.Unlock()
+
+									var events = model.FixedProvider[[]kafka.Message]([]kafka.Message{})
+									var dropped model.Provider[[]kafka.Message]
+									txErr := db.Transaction(func(tx *gorm.DB) error {
+										invId, err := GetInventoryIdByType(tx)(ctx)(characterId, Type(inventoryType))()
+										if err != nil {
+											l.WithError(err).Errorf("Unable to locate inventory [%d] for character [%d].", inventoryType, characterId)
+											return err
+										}
+
+										i, err := item.GetBySlot(tx)(ctx)(invId, source)
+										if err != nil {
+											l.WithError(err).Errorf("Unable to retrieve item in slot [%d].", source)
+											return err
+										}
+
+										initialQuantity := i.Quantity()
+
+										if initialQuantity <= uint32(quantity) {
+											err = item.DeleteById(tx)(ctx)(i.Id())
+											if err != nil {
+												l.WithError(err).Errorf("Unable to drop item in slot [%d].", source)
+												return err
+											}
+											events = model.MergeSliceProvider(events, inventoryItemRemoveProvider(characterId, i.ItemId(), i.Slot()))
+											dropped = droppedEventProvider(characterId, i.ItemId(), initialQuantity, 0)
+											return nil
+										}
+
+										newQuantity := initialQuantity - uint32(quantity)
+										err = item.UpdateQuantity(tx)(ctx)(i.Id(), newQuantity)
+										if err != nil {
+											l.WithError(err).Errorf("Unable to drop [%d] item in slot [%d].", quantity, source)
+											return err
+										}
+										events = model.MergeSliceProvider(events, inventoryItemUpdateProvider(characterId)(i.ItemId())(newQuantity, i.Slot()))
+										dropped = droppedEventProvider(characterId, i.ItemId(), uint32(quantity), 0)
+										return nil
+									})
+									if txErr != nil {
+										l.WithError(txErr).Errorf("Unable to complete dropping item for character [%d].", characterId)
+										return txErr
+									}
+									return emitDrop(l)(eventProducer)(characterId, events, dropped)
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func dropEquip(l logrus.FieldLogger) func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(droppedEventProvider droppedEventFunc) AssetDropper {
+	return func(db *gorm.DB) func(ctx context.Context) func(eventProducer producer.Provider) func(droppedEventProvider droppedEventFunc) AssetDropper {
+		return func(ctx context.Context) func(eventProducer producer.Provider) func(droppedEventProvider droppedEventFunc) AssetDropper {
+			return func(eventProducer producer.Provider) func(droppedEventProvider droppedEventFunc) AssetDropper {
+				return func(droppedEventProvider droppedEventFunc) AssetDropper {
+					return func(characterId uint32) func(source int16) func(quantity int16) error {
+						return func(source int16) func(quantity int16) error {
+							return func(quantity int16) error {
+								l.Debugf("Received request to drop item at [%d] for character [%d].", source, characterId)
+								invLock := GetLockRegistry().GetById(characterId, TypeValueEquip)
+								invLock.Lock()
+								defer invLock.Unlock()
+
+								var events = model.FixedProvider[[]kafka.Message]([]kafka.Message{})
+								var e equipable.Model
+								txErr := db.Transaction(func(tx *gorm.DB) error {
+									var err error
+									e, err = equipable.GetBySlot(tx)(ctx)(characterId, source)
+									if err != nil {
+										l.WithError(err).Errorf("Unable to retrieve equipment in slot [%d].", source)
+										return err
+									}
+									// The statistics are kept, as the dropped equipment is still in the world.
+									err = equipable.DropByReferenceId(l)(tx)(ctx)(e.ReferenceId())
+									if err != nil {
+										l.WithError(err).Errorf("Unable to drop equipment in slot [%d].", source)
+										return err
+									}
+									events = model.MergeSliceProvider(events, inventoryItemRemoveProvider(characterId, e.ItemId(), e.Slot()))
+									return nil
+								})
+								if txErr != nil {
+									l.WithError(txErr).Errorf("Unable to complete dropping item for character [%d].", characterId)
+									return txErr
+								}
+								// The statistics are looked up once the transaction completes, as they may be stored locally.
+								dropped := droppedEventProvider(characterId, e.ItemId(), 1, e.ReferenceId())
+								return emitDrop(l)(eventProducer)(characterId, events, dropped)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func emitDrop(l logrus.FieldLogger) func(eventProducer producer.Provider) func(characterId uint32, events model.Provider[[]kafka.Message], dropped model.Provider[[]kafka.Message]) error {
+	return func(eventProducer producer.Provider) func(characterId uint32, events model.Provider[[]kafka.Message], dropped model.Provider[[]kafka.Message]) error {
+		return func(characterId uint32, events model.Provider[[]kafka.Message], dropped model.Provider[[]kafka.Message]) error {
+			err := eventProducer(EnvEventInventoryChanged)(events)
+			if err != nil {
+				l.WithError(err).Errorf("Unable to convey inventory modifications to character [%d].", characterId)
+				return err
+			}
+			err = eventProducer(EnvEventTopicDrop)(dropped)
+			if err != nil {
+				l.WithError(err).Errorf("Unable to announce item dropped by character [%d].", characterId)
+			}
+			return err
+		}
+	}
+}
@@ -3,14 +3,20 @@ package inventory_test
 import (
 	"atlas-character/asset"
 	"atlas-character/character"
+	"atlas-character/database"
 	"atlas-character/equipable"
 	statistics2 "atlas-character/equipable/statistics"
 	"atlas-character/equipment"
 	"atlas-character/equipment/slot"
 	"atlas-character/inventory"
 	"atlas-character/inventory/item"
+	"atlas-character/inventory/item/information"
 	"atlas-character/kafka/producer"
+	"atlas-character/skill"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	producer2 "github.com/Chronicle20/atlas-kafka/producer"
 	"github.com/Chronicle20/atlas-model/model"
 	tenant "github.com/Chronicle20/atlas-tenant"
@@ -20,14 +26,22 @@ import (
 	"github.com/sirupsen/logrus/hooks/test"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"math"
+	"net/http"
+	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 )
 
 func testDatabase(t *testing.T) *gorm.DB {
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
 	if err != nil {
 		t.Fatalf("Failed to connect to database: %v", err)
 	}
+	if err := database.RegisterTenantCallbacks(db); err != nil {
+		t.Fatalf("Failed to register tenant scoping: %v", err)
+	}
 
 	var migrators []func(db *gorm.DB) error
 	migrators = append(migrators, character.Migration, inventory.Migration, item.Migration, equipable.Migration)
@@ -65,6 +79,16 @@ func testProducer(output *[]kafka.Message) producer.Provider {
 	}
 }
 
+func testSlotMaxLookup(slotMax uint32) inventory.SlotMaxLookup {
+	return func(characterId uint32) func(itemId uint32) inventory.SlotMaxProvider {
+		return func(itemId uint32) inventory.SlotMaxProvider {
+			return func() (uint32, error) {
+				return slotMax, nil
+			}
+		}
+	}
+}
+
 func TestAdjustingEquipment(t *testing.T) {
 	l := testLogger()
 	db := testDatabase(t)
@@ -74,23 +98,23 @@ func TestAdjustingEquipment(t *testing.T) {
 	var createMessages = make([]kafka.Message, 0)
 	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).SetExperience(0).Build()
 
-	c, err := character.Create(l)(db)(tctx)(testProducer(&createMessages))(input)
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
 	if err != nil {
 		t.Fatalf("Failed to create model: %v", err)
 	}
 
 	// Create inventory items
-	top := createAndVerifyMockEquip(t)(l)(db)(tctx)(c.Id())(1040010)
-	bottom := createAndVerifyMockEquip(t)(l)(db)(tctx)(c.Id())(1060002)
-	overall := createAndVerifyMockEquip(t)(l)(db)(tctx)(c.Id())(1050018)
+	top := createAndVerifyMockEquip(t)(l)(db.WithContext(tctx))(tctx)(c.Id())(1040010)
+	bottom := createAndVerifyMockEquip(t)(l)(db.WithContext(tctx))(tctx)(c.Id())(1060002)
+	overall := createAndVerifyMockEquip(t)(l)(db.WithContext(tctx))(tctx)(c.Id())(1050018)
 	t.Logf("Top [%d], Bottom [%d], Overall [%d].", top.Slot(), bottom.Slot(), overall.Slot())
 
 	var equipMessages = make([]kafka.Message, 0)
-	equipFunc := inventory.EquipItemForCharacter(l)(db)(tctx)(model.Flip(equipable.GetNextFreeSlot(l))(tctx))(testProducer(&equipMessages))(c.Id())
+	equipFunc := inventory.EquipItemForCharacter(l)(db.WithContext(tctx))(tctx)(model.Flip(equipable.GetNextFreeSlot(l)(inventory.GetCapacity))(tctx))(equipment.WeaponTypeTwoHandedProvider)(testProducer(&equipMessages))(c.Id())
 
 	// Equip Top to start.
 	equipFunc(top.Slot())(equipment.FixedDestinationProvider(int16(slot.PositionTop)))
-	equippedTop, err := equipable.GetBySlot(db)(tctx)(c.Id(), int16(slot.PositionTop))
+	equippedTop, err := equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionTop))
 	if err != nil {
 		t.Fatalf("Failed to retreive created item.")
 	}
@@ -100,7 +124,7 @@ func TestAdjustingEquipment(t *testing.T) {
 
 	// Equip Bottom to start.
 	equipFunc(bottom.Slot())(equipment.FixedDestinationProvider(int16(slot.PositionBottom)))
-	equippedBottom, err := equipable.GetBySlot(db)(tctx)(c.Id(), int16(slot.PositionBottom))
+	equippedBottom, err := equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionBottom))
 	if err != nil {
 		t.Fatalf("Failed to retreive created item.")
 	}
@@ -110,21 +134,21 @@ func TestAdjustingEquipment(t *testing.T) {
 
 	// Equip Overall. This should take tops place, and unequip bottom. Top should be in overall slot, bottom should be in first available.
 	equipFunc(overall.Slot())(equipment.FixedDestinationProvider(int16(slot.PositionOverall)))
-	equippedOverall, err := equipable.GetBySlot(db)(tctx)(c.Id(), int16(slot.PositionOverall))
+	equippedOverall, err := equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionOverall))
 	if err != nil {
 		t.Fatalf("Failed to retreive created item.")
 	}
 	if !validateEquipable(equippedOverall, EquipableItemIdValidator(1050018)) {
 		t.Fatalf("Equiping of Bottom failed validation.")
 	}
-	equippedTop, err = equipable.GetBySlot(db)(tctx)(c.Id(), 3)
+	equippedTop, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), 3)
 	if err != nil {
 		t.Fatalf("Failed to retreive created item.")
 	}
 	if !validateEquipable(equippedTop, EquipableItemIdValidator(1040010)) {
 		t.Fatalf("Unequiping of Top failed validation.")
 	}
-	equippedBottom, err = equipable.GetBySlot(db)(tctx)(c.Id(), 1)
+	equippedBottom, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), 1)
 	if err != nil {
 		t.Fatalf("Failed to retreive created item.")
 	}
@@ -134,14 +158,14 @@ func TestAdjustingEquipment(t *testing.T) {
 
 	// Equip Top next. Overall should take tops place, top should be equipped.
 	equipFunc(3)(equipment.FixedDestinationProvider(int16(slot.PositionTop)))
-	equippedTop, err = equipable.GetBySlot(db)(tctx)(c.Id(), int16(slot.PositionTop))
+	equippedTop, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionTop))
 	if err != nil {
 		t.Fatalf("Failed to retreive created item.")
 	}
 	if !validateEquipable(equippedTop, EquipableItemIdValidator(1040010)) {
 		t.Fatalf("Equiping of Top failed validation.")
 	}
-	equippedOverall, err = equipable.GetBySlot(db)(tctx)(c.Id(), 3)
+	equippedOverall, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), 3)
 	if err != nil {
 		t.Fatalf("Failed to retreive created item.")
 	}
@@ -151,7 +175,7 @@ func TestAdjustingEquipment(t *testing.T) {
 
 	// Equip Bottom again.
 	equipFunc(1)(equipment.FixedDestinationProvider(int16(slot.PositionBottom)))
-	equippedBottom, err = equipable.GetBySlot(db)(tctx)(c.Id(), int16(slot.PositionBottom))
+	equippedBottom, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionBottom))
 	if err != nil {
 		t.Fatalf("Failed to retreive created item.")
 	}
@@ -161,21 +185,21 @@ func TestAdjustingEquipment(t *testing.T) {
 
 	// Equip Overall. This should take tops place, and unequip bottom. Top should be in overall slot, bottom should be in first available.
 	equipFunc(3)(equipment.FixedDestinationProvider(int16(slot.PositionOverall)))
-	equippedOverall, err = equipable.GetBySlot(db)(tctx)(c.Id(), int16(slot.PositionOverall))
+	equippedOverall, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionOverall))
 	if err != nil {
 		t.Fatalf("Failed to retreive created item.")
 	}
 	if !validateEquipable(equippedOverall, EquipableItemIdValidator(1050018)) {
 		t.Fatalf("Equiping of Bottom failed validation.")
 	}
-	equippedTop, err = equipable.GetBySlot(db)(tctx)(c.Id(), 3)
+	equippedTop, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), 3)
 	if err != nil {
 		t.Fatalf("Failed to retreive created item.")
 	}
 	if !validateEquipable(equippedTop, EquipableItemIdValidator(1040010)) {
 		t.Fatalf("Unequiping of Top failed validation.")
 	}
-	equippedBottom, err = equipable.GetBySlot(db)(tctx)(c.Id(), 1)
+	equippedBottom, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), 1)
 	if err != nil {
 		t.Fatalf("Failed to retreive created item.")
 	}
@@ -185,14 +209,14 @@ func TestAdjustingEquipment(t *testing.T) {
 
 	// Equip Bottom again, overall should go to next free slot.
 	equipFunc(1)(equipment.FixedDestinationProvider(int16(slot.PositionBottom)))
-	equippedBottom, err = equipable.GetBySlot(db)(tctx)(c.Id(), int16(slot.PositionBottom))
+	equippedBottom, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionBottom))
 	if err != nil {
 		t.Fatalf("Failed to retreive created item.")
 	}
 	if !validateEquipable(equippedBottom, EquipableItemIdValidator(1060002)) {
 		t.Fatalf("Equiping of Bottom failed validation.")
 	}
-	equippedOverall, err = equipable.GetBySlot(db)(tctx)(c.Id(), 1)
+	equippedOverall, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), 1)
 	if err != nil {
 		t.Fatalf("Failed to retreive created item.")
 	}
@@ -202,7 +226,7 @@ func TestAdjustingEquipment(t *testing.T) {
 
 	// Equip Top next.
 	equipFunc(3)(equipment.FixedDestinationProvider(int16(slot.PositionTop)))
-	equippedTop, err = equipable.GetBySlot(db)(tctx)(c.Id(), int16(slot.PositionTop))
+	equippedTop, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionTop))
 	if err != nil {
 		t.Fatalf("Failed to retreive created item.")
 	}
@@ -211,9 +235,9 @@ func TestAdjustingEquipment(t *testing.T) {
 	}
 
 	var unequipMessages = make([]kafka.Message, 0)
-	unequipFunc := inventory.UnequipItemForCharacter(l)(db)(tctx)(model.Flip(equipable.GetNextFreeSlot(l))(tctx))(testProducer(&unequipMessages))(c.Id())
+	unequipFunc := inventory.UnequipItemForCharacter(l)(db.WithContext(tctx))(tctx)(model.Flip(equipable.GetNextFreeSlot(l)(inventory.GetCapacity))(tctx))(testProducer(&unequipMessages))(c.Id())
 	unequipFunc(int16(slot.PositionTop))
-	equippedTop, err = equipable.GetBySlot(db)(tctx)(c.Id(), 2)
+	equippedTop, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), 2)
 	if err != nil {
 		t.Fatalf("Failed to retreive created item.")
 	}
@@ -221,7 +245,7 @@ func TestAdjustingEquipment(t *testing.T) {
 		t.Fatalf("Unequiping of Top failed validation.")
 	}
 	unequipFunc(int16(slot.PositionBottom))
-	equippedBottom, err = equipable.GetBySlot(db)(tctx)(c.Id(), 3)
+	equippedBottom, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), 3)
 	if err != nil {
 		t.Fatalf("Failed to retreive created item.")
 	}
@@ -230,6 +254,230 @@ func TestAdjustingEquipment(t *testing.T) {
 	}
 }
 
+func TestEquipRequirementNotMet(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	weapon := createAndVerifyMockEquip(t)(l)(db.WithContext(tctx))(tctx)(c.Id())(1302000)
+
+	var validated int16
+	rejectLevel := func(_ *gorm.DB) equipment.Validator {
+		return func(itemId uint32, destination int16) error {
+			validated = destination
+			return equipment.RequirementError{ItemId: itemId, Reason: equipment.RequirementReasonLevel, Required: 120, Actual: 1}
+		}
+	}
+
+	var equipMessages = make([]kafka.Message, 0)
+	err = inventory.EquipItemForCharacter(l)(db.WithContext(tctx))(tctx)(model.Flip(equipable.GetNextFreeSlot(l)(inventory.GetCapacity))(tctx))(equipment.WeaponTypeTwoHandedProvider)(testProducer(&equipMessages))(c.Id())(weapon.Slot())(equipment.FixedDestinationProvider(int16(slot.PositionWeapon)), rejectLevel)
+	var re equipment.RequirementError
+	if !errors.As(err, &re) || re.Reason != equipment.RequirementReasonLevel {
+		t.Fatalf("Expected level requirement error, got: %v", err)
+	}
+	if validated != int16(slot.PositionWeapon) {
+		t.Fatalf("Validator should be given the destination, was %d.", validated)
+	}
+	if len(equipMessages) != 0 {
+		t.Fatalf("No events should be emitted, was %d", len(equipMessages))
+	}
+	unequipped, err := equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), weapon.Slot())
+	if err != nil || !validateEquipable(unequipped, EquipableItemIdValidator(1302000)) {
+		t.Fatalf("Weapon should remain in its inventory slot.")
+	}
+}
+
+func TestEquipThroughConnection(t *testing.T) {
+	t.Setenv("DB_DRIVER", database.DriverSQLite)
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "atlas-character.db"))
+	t.Setenv("DB_CONNECT_ATTEMPTS", "1")
+	l := testLogger()
+	db := database.Connect(l, database.SetMigrations(
+		database.GoMigration(1, "create characters", character.Migration),
+		database.GoMigration(2, "create inventory", inventory.Migration),
+		database.GoMigration(3, "create items", item.Migration),
+		database.GoMigration(4, "create equipables", equipable.Migration),
+	))
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to retrieve connection pool: %v", err)
+	}
+	defer sqlDB.Close()
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+	weapon := createAndVerifyMockEquip(t)(l)(db.WithContext(tctx))(tctx)(c.Id())(1302000)
+
+	// The validator reads through the equip's transaction, which holds the only connection.
+	wearerExists := func(db *gorm.DB) equipment.Validator {
+		return func(itemId uint32, _ int16) error {
+			_, err := character.GetById(db.WithContext(tctx))(tctx)()(c.Id())
+			return err
+		}
+	}
+
+	var equipMessages = make([]kafka.Message, 0)
+	done := make(chan error, 1)
+	go func() {
+		done <- inventory.EquipItemForCharacter(l)(db.WithContext(tctx))(tctx)(model.Flip(equipable.GetNextFreeSlot(l)(inventory.GetCapacity))(tctx))(equipment.WeaponTypeTwoHandedProvider)(testProducer(&equipMessages))(c.Id())(weapon.Slot())(equipment.FixedDestinationProvider(int16(slot.PositionWeapon)), wearerExists)
+	}()
+	select {
+	case err = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Equip deadlocked.")
+	}
+	if err != nil {
+		t.Fatalf("Failed to equip weapon: %v", err)
+	}
+	equipped, err := equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionWeapon))
+	if err != nil || !validateEquipable(equipped, EquipableItemIdValidator(1302000)) {
+		t.Fatalf("Weapon failed validation.")
+	}
+}
+
+func TestTwoHandedWeaponAndShield(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	oneHanded := createAndVerifyMockEquip(t)(l)(db.WithContext(tctx))(tctx)(c.Id())(1302000)
+	shield := createAndVerifyMockEquip(t)(l)(db.WithContext(tctx))(tctx)(c.Id())(1092030)
+	twoHanded := createAndVerifyMockEquip(t)(l)(db.WithContext(tctx))(tctx)(c.Id())(1402001)
+
+	fsp := model.Flip(equipable.GetNextFreeSlot(l)(inventory.GetCapacity))(tctx)
+	equip := func(messages *[]kafka.Message, fsp func(db *gorm.DB) func(uint32) model.Provider[int16], source int16, destination slot.Position) error {
+		return inventory.EquipItemForCharacter(l)(db.WithContext(tctx))(tctx)(fsp)(equipment.WeaponTypeTwoHandedProvider)(testProducer(messages))(c.Id())(source)(equipment.FixedDestinationProvider(int16(destination)))
+	}
+
+	var messages = make([]kafka.Message, 0)
+	if err = equip(&messages, fsp, oneHanded.Slot(), slot.PositionWeapon); err != nil {
+		t.Fatalf("Failed to equip one-handed weapon: %v", err)
+	}
+	if err = equip(&messages, fsp, shield.Slot(), slot.PositionShield); err != nil {
+		t.Fatalf("Failed to equip shield: %v", err)
+	}
+
+	// Equipping a two-handed weapon while a shield is worn, with no free slot for the shield, should change nothing.
+	full := func(db *gorm.DB) func(uint32) model.Provider[int16] {
+		return func(uint32) model.Provider[int16] {
+			return model.ErrorProvider[int16](errors.New("inventory full"))
+		}
+	}
+	messages = make([]kafka.Message, 0)
+	if err = equip(&messages, full, twoHanded.Slot(), slot.PositionWeapon); err == nil {
+		t.Fatalf("Expected equip to fail when the shield cannot be unequipped.")
+	}
+	if len(messages) != 0 {
+		t.Fatalf("No events should be emitted on rollback, was %d", len(messages))
+	}
+	weapon, err := equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionWeapon))
+	if err != nil || !validateEquipable(weapon, EquipableItemIdValidator(1302000)) {
+		t.Fatalf("One-handed weapon should remain equipped.")
+	}
+	equippedShield, err := equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionShield))
+	if err != nil || !validateEquipable(equippedShield, EquipableItemIdValidator(1092030)) {
+		t.Fatalf("Shield should remain equipped.")
+	}
+
+	// Equipping a two-handed weapon unequips the shield.
+	messages = make([]kafka.Message, 0)
+	if err = equip(&messages, fsp, twoHanded.Slot(), slot.PositionWeapon); err != nil {
+		t.Fatalf("Failed to equip two-handed weapon: %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("Expected a move event for the weapon and the shield, was %d", len(messages))
+	}
+	weapon, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionWeapon))
+	if err != nil || !validateEquipable(weapon, EquipableItemIdValidator(1402001)) {
+		t.Fatalf("Two-handed weapon should be equipped.")
+	}
+	if _, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionShield)); err == nil {
+		t.Fatalf("Shield should be unequipped.")
+	}
+	unequippedShield, err := equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), 1)
+	if err != nil || !validateEquipable(unequippedShield, EquipableItemIdValidator(1092030)) {
+		t.Fatalf("Shield should be in the first free slot.")
+	}
+
+	// Equipping a shield unequips the two-handed weapon.
+	messages = make([]kafka.Message, 0)
+	if err = equip(&messages, fsp, 1, slot.PositionShield); err != nil {
+		t.Fatalf("Failed to equip shield: %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("Expected a move event for the shield and the weapon, was %d", len(messages))
+	}
+	if _, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionWeapon)); err == nil {
+		t.Fatalf("Two-handed weapon should be unequipped.")
+	}
+	unequippedWeapon, err := equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), 1)
+	if err != nil || !validateEquipable(unequippedWeapon, EquipableItemIdValidator(1402001)) {
+		t.Fatalf("Two-handed weapon should be in the first free slot.")
+	}
+}
+
+func TestEquipRings(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	first := createAndVerifyMockEquip(t)(l)(db.WithContext(tctx))(tctx)(c.Id())(1112400)
+	second := createAndVerifyMockEquip(t)(l)(db.WithContext(tctx))(tctx)(c.Id())(1112401)
+
+	ringDestination := func(itemId uint32, occupied equipment.SlotPredicate) model.Provider[int16] {
+		candidates := []int16{int16(slot.PositionRing1), int16(slot.PositionRing2), int16(slot.PositionRing3), int16(slot.PositionRing4)}
+		d, err := equipment.SelectDestination(candidates, equipment.AllSlotsAvailable, occupied)
+		if err != nil {
+			return model.ErrorProvider[int16](err)
+		}
+		return model.FixedProvider(d)
+	}
+
+	var equipMessages = make([]kafka.Message, 0)
+	equipFunc := inventory.EquipItemForCharacter(l)(db.WithContext(tctx))(tctx)(model.Flip(equipable.GetNextFreeSlot(l)(inventory.GetCapacity))(tctx))(equipment.WeaponTypeTwoHandedProvider)(testProducer(&equipMessages))(c.Id())
+	if err = equipFunc(first.Slot())(ringDestination); err != nil {
+		t.Fatalf("Failed to equip first ring: %v", err)
+	}
+	if err = equipFunc(second.Slot())(ringDestination); err != nil {
+		t.Fatalf("Failed to equip second ring: %v", err)
+	}
+
+	ring1, err := equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionRing1))
+	if err != nil || !validateEquipable(ring1, EquipableItemIdValidator(1112400)) {
+		t.Fatalf("First ring should be in ring slot 1.")
+	}
+	ring2, err := equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionRing2))
+	if err != nil || !validateEquipable(ring2, EquipableItemIdValidator(1112401)) {
+		t.Fatalf("Second ring should be in ring slot 2.")
+	}
+}
+
 type EquipableValidator func(equipable.Model) bool
 
 func EquipableItemIdValidator(itemId uint32) EquipableValidator {
@@ -260,12 +508,12 @@ func createAndVerifyMockEquip(t *testing.T) func(l logrus.FieldLogger) func(db *
 								return make([]kafka.Message, 0), nil
 							}
 						}
-						err := createMockEquipAsset(l)(db)(ctx)(iap)(characterId)(int8(inventory.TypeValueEquip))(itemId)
+						err := createMockEquipAsset(l)(db.WithContext(ctx))(ctx)(iap)(characterId)(int8(inventory.TypeValueEquip))(itemId)
 						if err != nil {
 							t.Fatalf("Failed to create item: %v", err)
 						}
 
-						wipE, err := equipable.GetBySlot(db)(ctx)(characterId, wipSlot)
+						wipE, err := equipable.GetBySlot(db.WithContext(ctx))(ctx)(characterId, wipSlot)
 						if err != nil {
 							t.Fatalf("Failed to retreive created item.")
 						}
@@ -291,7 +539,7 @@ func createMockEquipAsset(l logrus.FieldLogger) func(db *gorm.DB) func(ctx conte
 				return func(characterId uint32) func(inventoryType int8) func(itemId uint32) error {
 					return func(inventoryType int8) func(itemId uint32) error {
 						return func(itemId uint32) error {
-							invId, err := inventory.GetInventoryIdByType(db)(ctx)(characterId, inventory.Type(inventoryType))()
+							invId, err := inventory.GetInventoryIdByType(db.WithContext(ctx))(ctx)(characterId, inventory.Type(inventoryType))()
 							if err != nil {
 								l.WithError(err).Errorf("Unable to locate inventory [%d] for character [%d].", inventoryType, characterId)
 								return err
@@ -308,7 +556,7 @@ func createMockEquipAsset(l logrus.FieldLogger) func(db *gorm.DB) func(ctx conte
 									return statistics2.Model{}, nil
 								}
 							}
-							nac := equipable.CreateItem(l)(db)(ctx)(esc)(characterId)(invId, inventoryType)(itemId)
+							nac := equipable.CreateItem(l)(db.WithContext(ctx))(ctx)(inventory.GetCapacity)(esc)(characterId)(invId, inventoryType)(itemId)
 							aqu := asset.NoOpQuantityUpdater
 
 							_, err = inventory.CreateAsset(l)(eap, smp, nac, aqu, iap, iup, 1)()
@@ -331,7 +579,7 @@ func createMockItemAsset(l logrus.FieldLogger) func(db *gorm.DB) func(ctx contex
 				return func(inventoryType int8) func(itemId uint32) func(quantity uint32) error {
 					return func(itemId uint32) func(quantity uint32) error {
 						return func(quantity uint32) error {
-							invId, err := inventory.GetInventoryIdByType(db)(ctx)(characterId, inventory.Type(inventoryType))()
+							invId, err := inventory.GetInventoryIdByType(db.WithContext(ctx))(ctx)(characterId, inventory.Type(inventoryType))()
 							if err != nil {
 								l.WithError(err).Errorf("Unable to locate inventory [%d] for character [%d].", inventoryType, characterId)
 								return err
@@ -347,13 +595,13 @@ func createMockItemAsset(l logrus.FieldLogger) func(db *gorm.DB) func(ctx contex
 									return make([]kafka.Message, 0), nil
 								}
 							}
-							eap := item.AssetByItemIdProvider(db)(ctx)(invId)(itemId)
+							eap := item.AssetByItemIdProvider(db.WithContext(ctx))(ctx)(invId)(itemId)
 							smp := func() (uint32, error) {
 								// TODO properly look this up.
 								return 200, nil
 							}
-							nac := item.CreateItem(db)(ctx)(characterId)(invId, inventoryType)(itemId)
-							aqu := item.UpdateQuantity(db)(ctx)
+							nac := item.CreateItem(db.WithContext(ctx))(ctx)(inventory.GetCapacity)(characterId)(invId, inventoryType)(itemId)
+							aqu := item.UpdateQuantity(db.WithContext(ctx))(ctx)
 
 							_, err = inventory.CreateAsset(l)(eap, smp, nac, aqu, iap, iup, quantity)()
 							if err != nil {
@@ -376,28 +624,28 @@ func TestMove(t *testing.T) {
 	// Create character
 	var createMessages = make([]kafka.Message, 0)
 	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).SetExperience(0).Build()
-	c, err := character.Create(l)(db)(tctx)(testProducer(&createMessages))(input)
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
 	if err != nil {
 		t.Fatalf("Failed to create model: %v", err)
 	}
 
 	// Create inventory items
-	err = createMockItemAsset(l)(db)(tctx)(c.Id())(2)(2000000)(100)
+	err = createMockItemAsset(l)(db.WithContext(tctx))(tctx)(c.Id())(2)(2000000)(100)
 	if err != nil {
 		t.Fatalf("Failed to create item: %v", err)
 	}
 
-	err = createMockItemAsset(l)(db)(tctx)(c.Id())(2)(2000001)(150)
+	err = createMockItemAsset(l)(db.WithContext(tctx))(tctx)(c.Id())(2)(2000001)(150)
 	if err != nil {
 		t.Fatalf("Failed to create item: %v", err)
 	}
 
 	// validate inventory items
-	invId, err := inventory.GetInventoryIdByType(db)(tctx)(c.Id(), 2)()
+	invId, err := inventory.GetInventoryIdByType(db.WithContext(tctx))(tctx)(c.Id(), 2)()
 	if err != nil {
 		t.Fatalf("Failed to get inventory: %v", err)
 	}
-	i1, err := item.GetBySlot(db)(tctx)(invId, 1)
+	i1, err := item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 1)
 	if err != nil {
 		t.Fatalf("Failed to get inventory: %v", err)
 	}
@@ -405,7 +653,7 @@ func TestMove(t *testing.T) {
 		t.Fatalf("Item failed validation.")
 	}
 
-	i2, err := item.GetBySlot(db)(tctx)(invId, 2)
+	i2, err := item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 2)
 	if err != nil {
 		t.Fatalf("Failed to get inventory: %v", err)
 	}
@@ -415,14 +663,14 @@ func TestMove(t *testing.T) {
 
 	// test move
 	var moveItemMessages = make([]kafka.Message, 0)
-	err = inventory.Move(l)(db)(tctx)(testProducer(&moveItemMessages))(2)(c.Id())(2)(1)
+	err = inventory.Move(l)(db.WithContext(tctx))(tctx)(testProducer(&moveItemMessages))(testSlotMaxLookup(200))(2)(c.Id())(2)(1)
 	if err != nil {
 		t.Fatalf("Failed to move item: %v", err)
 	}
 	if len(moveItemMessages) != 1 {
 		t.Fatalf("Failed to move item: %v", moveItemMessages)
 	}
-	i3, err := item.GetBySlot(db)(tctx)(invId, 1)
+	i3, err := item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 1)
 	if err != nil {
 		t.Fatalf("Failed to get inventory: %v", err)
 	}
@@ -430,7 +678,7 @@ func TestMove(t *testing.T) {
 		t.Fatalf("Item failed validation.")
 	}
 
-	i4, err := item.GetBySlot(db)(tctx)(invId, 2)
+	i4, err := item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 2)
 	if err != nil {
 		t.Fatalf("Failed to get inventory: %v", err)
 	}
@@ -461,3 +709,835 @@ func validateItem(i item.Model, validators ...ItemValidator) bool {
 	}
 	return true
 }
+
+func TestInventoryFull(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).SetExperience(0).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	useId, err := inventory.GetInventoryIdByType(db.WithContext(tctx))(tctx)(c.Id(), inventory.TypeValueUse)()
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	equipId, err := inventory.GetInventoryIdByType(db.WithContext(tctx))(tctx)(c.Id(), inventory.TypeValueEquip)()
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	err = db.Exec("UPDATE inventory SET capacity = ? WHERE id IN (?, ?)", 2, useId, equipId).Error
+	if err != nil {
+		t.Fatalf("Failed to reduce capacity: %v", err)
+	}
+
+	err = createMockItemAsset(l)(db.WithContext(tctx))(tctx)(c.Id())(2)(2000000)(150)
+	if err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+
+	// 300 more tops up slot 1, fills slot 2 and has nowhere to put the remaining 50.
+	err = db.Transaction(func(tx *gorm.DB) error {
+		return createMockItemAsset(l)(tx.WithContext(tctx))(tctx)(c.Id())(2)(2000000)(300)
+	})
+	if !errors.Is(err, inventory.ErrInventoryFull) {
+		t.Fatalf("Expected inventory full, got: %v", err)
+	}
+
+	i1, err := item.GetBySlot(db.WithContext(tctx))(tctx)(useId, 1)
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	if !validateItem(i1, ItemIdItemValidator(2000000), QuantityItemValidator(150)) {
+		t.Fatalf("Partial grant was not rolled back.")
+	}
+	_, err = item.GetBySlot(db.WithContext(tctx))(tctx)(useId, 2)
+	if err == nil {
+		t.Fatalf("Partial grant was not rolled back.")
+	}
+
+	iap := func(quantity uint32, slot int16) model.Provider[[]kafka.Message] {
+		return model.FixedProvider[[]kafka.Message](nil)
+	}
+	for _, itemId := range []uint32{1302000, 1312004} {
+		err = createMockEquipAsset(l)(db.WithContext(tctx))(tctx)(iap)(c.Id())(1)(itemId)
+		if err != nil {
+			t.Fatalf("Failed to create equipable: %v", err)
+		}
+	}
+	err = createMockEquipAsset(l)(db.WithContext(tctx))(tctx)(iap)(c.Id())(1)(1322005)
+	var fe inventory.InventoryFullError
+	if !errors.As(err, &fe) || fe.InventoryId != equipId || fe.Capacity != 2 {
+		t.Fatalf("Expected equip inventory [%d] full at capacity [2], got: %v", equipId, err)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).SetExperience(0).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+	before := c.GetInventory().Etc().Capacity()
+
+	var expandMessages = make([]kafka.Message, 0)
+	err = inventory.Expand(l)(db.WithContext(tctx))(tctx)(testProducer(&expandMessages))(c.Id(), inventory.TypeValueETC, before+4)
+	if err != nil {
+		t.Fatalf("Failed to expand inventory: %v", err)
+	}
+	if len(expandMessages) != 1 {
+		t.Fatalf("Expected a capacity changed event, got: %v", expandMessages)
+	}
+
+	inv, err := inventory.GetInventories(l)(db.WithContext(tctx))(tctx)(c.Id())
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	if inv.Etc().Capacity() != before+4 {
+		t.Fatalf("Capacity expected=%d, got=%d", before+4, inv.Etc().Capacity())
+	}
+	if inv.Useable().Capacity() != before {
+		t.Fatalf("Capacity of other inventories changed.")
+	}
+
+	err = inventory.Expand(l)(db.WithContext(tctx))(tctx)(testProducer(&expandMessages))(c.Id(), inventory.TypeValueETC, before+4)
+	if !errors.Is(err, inventory.ErrMaxCapacity) {
+		t.Fatalf("Expected maximum capacity, got: %v", err)
+	}
+	if len(expandMessages) != 1 {
+		t.Fatalf("Unexpected event emitted: %v", expandMessages)
+	}
+}
+
+func TestConsume(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).SetExperience(0).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	err = createMockItemAsset(l)(db.WithContext(tctx))(tctx)(c.Id())(2)(2000000)(10)
+	if err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+	invId, err := inventory.GetInventoryIdByType(db.WithContext(tctx))(tctx)(c.Id(), inventory.TypeValueUse)()
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+
+	var consumeMessages = make([]kafka.Message, 0)
+	err = inventory.Consume(l)(db.WithContext(tctx))(tctx)(testProducer(&consumeMessages))(c.Id(), inventory.TypeValueUse, 1, 4)
+	if err != nil {
+		t.Fatalf("Failed to consume item: %v", err)
+	}
+	i, err := item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 1)
+	if err != nil {
+		t.Fatalf("Failed to get item: %v", err)
+	}
+	if !validateItem(i, ItemIdItemValidator(2000000), QuantityItemValidator(6)) {
+		t.Fatalf("Item failed validation.")
+	}
+
+	err = inventory.Consume(l)(db.WithContext(tctx))(tctx)(testProducer(&consumeMessages))(c.Id(), inventory.TypeValueUse, 1, 7)
+	if !errors.Is(err, inventory.ErrInsufficientQuantity) {
+		t.Fatalf("Expected insufficient quantity, got: %v", err)
+	}
+
+	err = inventory.Consume(l)(db.WithContext(tctx))(tctx)(testProducer(&consumeMessages))(c.Id(), inventory.TypeValueUse, 1, 6)
+	if err != nil {
+		t.Fatalf("Failed to consume item: %v", err)
+	}
+	_, err = item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 1)
+	if err == nil {
+		t.Fatalf("Expected item to be removed.")
+	}
+	if len(consumeMessages) != 2 {
+		t.Fatalf("Expected an update and a remove event, got: %v", consumeMessages)
+	}
+}
+
+// seedLocalEquipable stores an equipable with locally stored statistics directly, without generating them from game
+// data.
+func seedLocalEquipable(t *testing.T, db *gorm.DB, tt tenant.Model, inventoryId uint32, itemId uint32, slot int16, referenceId uint32) {
+	err := db.Exec("INSERT INTO equipable_statistics (tenant_id, id, item_id, strength, dexterity, intelligence, luck, hp, mp, weapon_attack, magic_attack, weapon_defense, magic_defense, accuracy, avoidability, hands, speed, jump, slots) VALUES (?, ?, ?, 0, 0, 0, 0, 0, 0, 17, 0, 0, 0, 0, 0, 0, 0, 0, 7)", tt.Id(), referenceId, itemId).Error
+	if err != nil {
+		t.Fatalf("Failed to create statistics: %v", err)
+	}
+	err = db.Exec("INSERT INTO equipables (tenant_id, inventory_id, item_id, slot, reference_id) VALUES (?, ?, ?, ?, ?)", tt.Id(), inventoryId, itemId, slot, referenceId).Error
+	if err != nil {
+		t.Fatalf("Failed to create equipable: %v", err)
+	}
+}
+
+func TestConsumeEquipment(t *testing.T) {
+	t.Setenv("EQUIPABLE_STATISTICS_STORE", statistics2.StoreLocal)
+	l := testLogger()
+	db := testDatabase(t)
+	if err := statistics2.Migration(db); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	tt := testTenant()
+	tctx := tenant.WithContext(context.Background(), tt)
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+	equipId, err := inventory.GetInventoryIdByType(db.WithContext(tctx))(tctx)(c.Id(), inventory.TypeValueEquip)()
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	seedLocalEquipable(t, db, tt, equipId, 1302000, 1, 11)
+
+	var consumeMessages = make([]kafka.Message, 0)
+	err = inventory.Consume(l)(db.WithContext(tctx))(tctx)(testProducer(&consumeMessages))(c.Id(), inventory.TypeValueEquip, 1, 1)
+	if err != nil {
+		t.Fatalf("Failed to consume equipment: %v", err)
+	}
+	if len(consumeMessages) != 1 {
+		t.Fatalf("Expected a remove event, got: %v", consumeMessages)
+	}
+	_, err = equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), 1)
+	if err == nil {
+		t.Fatalf("Expected equipment to be removed.")
+	}
+	if _, ok := statistics2.GetByIds(l, db.WithContext(tctx), tctx)([]uint32{11})[11]; ok {
+		t.Fatalf("Expected statistics to be deleted with the equipment.")
+	}
+}
+
+func TestConsumeWornEquipment(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	weapon := createAndVerifyMockEquip(t)(l)(db.WithContext(tctx))(tctx)(c.Id())(1302000)
+	var equipMessages = make([]kafka.Message, 0)
+	err = inventory.EquipItemForCharacter(l)(db.WithContext(tctx))(tctx)(model.Flip(equipable.GetNextFreeSlot(l)(inventory.GetCapacity))(tctx))(equipment.WeaponTypeTwoHandedProvider)(testProducer(&equipMessages))(c.Id())(weapon.Slot())(equipment.FixedDestinationProvider(int16(slot.PositionWeapon)))
+	if err != nil {
+		t.Fatalf("Failed to equip weapon: %v", err)
+	}
+
+	var consumeMessages = make([]kafka.Message, 0)
+	err = inventory.Consume(l)(db.WithContext(tctx))(tctx)(testProducer(&consumeMessages))(c.Id(), inventory.TypeValueEquip, int16(slot.PositionWeapon), 1)
+	if !errors.Is(err, inventory.ErrInvalidSlot) {
+		t.Fatalf("Expected invalid slot, got: %v", err)
+	}
+	if len(consumeMessages) != 0 {
+		t.Fatalf("No events should be emitted, was %d", len(consumeMessages))
+	}
+	worn, err := equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionWeapon))
+	if err != nil || !validateEquipable(worn, EquipableItemIdValidator(1302000)) {
+		t.Fatalf("Weapon should remain equipped.")
+	}
+}
+
+func TestMoveMerge(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).SetExperience(0).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	// Two partial stacks of the same item.
+	invId, err := inventory.GetInventoryIdByType(db.WithContext(tctx))(tctx)(c.Id(), inventory.TypeValueUse)()
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	for slot, quantity := range map[int16]uint32{1: 80, 2: 70} {
+		_, err = item.CreateItemInSlot(db.WithContext(tctx))(tctx)(invId)(2000000, quantity, slot)
+		if err != nil {
+			t.Fatalf("Failed to create item: %v", err)
+		}
+	}
+
+	// Moving slot 2 onto slot 1 tops slot 1 up to 100 and leaves the remainder in slot 2.
+	var moveMessages = make([]kafka.Message, 0)
+	err = inventory.Move(l)(db.WithContext(tctx))(tctx)(testProducer(&moveMessages))(testSlotMaxLookup(100))(2)(c.Id())(2)(1)
+	if err != nil {
+		t.Fatalf("Failed to move item: %v", err)
+	}
+	if len(moveMessages) != 2 {
+		t.Fatalf("Expected two update events, got: %v", moveMessages)
+	}
+	i1, err := item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 1)
+	if err != nil || !validateItem(i1, ItemIdItemValidator(2000000), QuantityItemValidator(100)) {
+		t.Fatalf("Item failed validation.")
+	}
+	i2, err := item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 2)
+	if err != nil || !validateItem(i2, ItemIdItemValidator(2000000), QuantityItemValidator(50)) {
+		t.Fatalf("Item failed validation.")
+	}
+
+	// Moving slot 1 onto slot 2 fits entirely and removes slot 1.
+	moveMessages = make([]kafka.Message, 0)
+	err = inventory.Move(l)(db.WithContext(tctx))(tctx)(testProducer(&moveMessages))(testSlotMaxLookup(200))(2)(c.Id())(1)(2)
+	if err != nil {
+		t.Fatalf("Failed to move item: %v", err)
+	}
+	_, err = item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 1)
+	if err == nil {
+		t.Fatalf("Expected merged item to be removed.")
+	}
+	i2, err = item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 2)
+	if err != nil || !validateItem(i2, ItemIdItemValidator(2000000), QuantityItemValidator(150)) {
+		t.Fatalf("Item failed validation.")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).SetExperience(0).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	err = createMockItemAsset(l)(db.WithContext(tctx))(tctx)(c.Id())(2)(2000000)(100)
+	if err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+	invId, err := inventory.GetInventoryIdByType(db.WithContext(tctx))(tctx)(c.Id(), inventory.TypeValueUse)()
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+
+	var splitMessages = make([]kafka.Message, 0)
+	err = inventory.Split(l)(db.WithContext(tctx))(tctx)(testProducer(&splitMessages))(c.Id(), inventory.TypeValueUse, 1, 5, 30)
+	if err != nil {
+		t.Fatalf("Failed to split item: %v", err)
+	}
+	if len(splitMessages) != 2 {
+		t.Fatalf("Expected an update and an add event, got: %v", splitMessages)
+	}
+	i1, err := item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 1)
+	if err != nil || !validateItem(i1, ItemIdItemValidator(2000000), QuantityItemValidator(70)) {
+		t.Fatalf("Item failed validation.")
+	}
+	i5, err := item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 5)
+	if err != nil || !validateItem(i5, ItemIdItemValidator(2000000), QuantityItemValidator(30)) {
+		t.Fatalf("Item failed validation.")
+	}
+
+	err = inventory.Split(l)(db.WithContext(tctx))(tctx)(testProducer(&splitMessages))(c.Id(), inventory.TypeValueUse, 1, 5, 10)
+	if !errors.Is(err, inventory.ErrSlotOccupied) {
+		t.Fatalf("Expected slot occupied, got: %v", err)
+	}
+	err = inventory.Split(l)(db.WithContext(tctx))(tctx)(testProducer(&splitMessages))(c.Id(), inventory.TypeValueUse, 1, 6, 70)
+	if !errors.Is(err, inventory.ErrInsufficientQuantity) {
+		t.Fatalf("Expected insufficient quantity, got: %v", err)
+	}
+}
+
+func TestGatherAndSort(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).SetExperience(0).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+	invId, err := inventory.GetInventoryIdByType(db.WithContext(tctx))(tctx)(c.Id(), inventory.TypeValueUse)()
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+
+	type stack struct {
+		itemId   uint32
+		quantity uint32
+	}
+	initial := map[int16]stack{
+		2: {2000001, 30},
+		4: {2000000, 60},
+		7: {2000001, 90},
+		9: {2000000, 40},
+	}
+	for slot, s := range initial {
+		_, err = item.CreateItemInSlot(db.WithContext(tctx))(tctx)(invId)(s.itemId, s.quantity, slot)
+		if err != nil {
+			t.Fatalf("Failed to create item: %v", err)
+		}
+	}
+
+	var gatherMessages = make([]kafka.Message, 0)
+	err = inventory.Gather(l)(db.WithContext(tctx))(tctx)(testProducer(&gatherMessages))(c.Id(), inventory.TypeValueUse)
+	if err != nil {
+		t.Fatalf("Failed to gather inventory: %v", err)
+	}
+	if len(gatherMessages) != 4 {
+		t.Fatalf("Expected a move event per relocated item, got: %v", gatherMessages)
+	}
+	for slot, expected := range []stack{{2000001, 30}, {2000000, 60}, {2000001, 90}, {2000000, 40}} {
+		i, err := item.GetBySlot(db.WithContext(tctx))(tctx)(invId, int16(slot+1))
+		if err != nil || !validateItem(i, ItemIdItemValidator(expected.itemId), QuantityItemValidator(expected.quantity)) {
+			t.Fatalf("Item in slot [%d] failed validation.", slot+1)
+		}
+	}
+
+	var sortMessages = make([]kafka.Message, 0)
+	err = inventory.Sort(l)(db.WithContext(tctx))(tctx)(testProducer(&sortMessages))(testSlotMaxLookup(100))(c.Id(), inventory.TypeValueUse)
+	if err != nil {
+		t.Fatalf("Failed to sort inventory: %v", err)
+	}
+	// Slot 2 tops up slot 4 and slot 1 tops up slot 3, leaving slot 4 empty. Only slot 2 is then out of place.
+	var sortTypes []string
+	for _, m := range sortMessages {
+		var e struct {
+			Type string `json:"type"`
+			Slot int16  `json:"slot"`
+		}
+		err = json.Unmarshal(m.Value, &e)
+		if err != nil {
+			t.Fatalf("Failed to decode inventory changed event: %v", err)
+		}
+		sortTypes = append(sortTypes, fmt.Sprintf("%s:%d", e.Type, e.Slot))
+	}
+	expectedTypes := []string{
+		inventory.ChangedTypeUpdate + ":2",
+		inventory.ChangedTypeRemove + ":4",
+		inventory.ChangedTypeUpdate + ":1",
+		inventory.ChangedTypeUpdate + ":3",
+		inventory.ChangedTypeMove + ":1",
+	}
+	if !slices.Equal(sortTypes, expectedTypes) {
+		t.Fatalf("Expected events %v, got: %v", expectedTypes, sortTypes)
+	}
+	for slot, expected := range []stack{{2000000, 100}, {2000001, 100}, {2000001, 20}} {
+		i, err := item.GetBySlot(db.WithContext(tctx))(tctx)(invId, int16(slot+1))
+		if err != nil || !validateItem(i, ItemIdItemValidator(expected.itemId), QuantityItemValidator(expected.quantity)) {
+			t.Fatalf("Item in slot [%d] failed validation.", slot+1)
+		}
+	}
+	_, err = item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 4)
+	if err == nil {
+		t.Fatalf("Expected merged stack to be removed.")
+	}
+}
+
+func TestPickup(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).SetExperience(0).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	// The dropped equipable's statistics are supplied rather than generated.
+	var supplied []uint32
+	var esc statistics2.CreatorProvider = func(db *gorm.DB) statistics2.Creator {
+		return func(itemId uint32) model.Provider[statistics2.Model] {
+			supplied = append(supplied, itemId)
+			return model.FixedProvider(statistics2.Model{})
+		}
+	}
+	var pickupMessages = make([]kafka.Message, 0)
+	err = inventory.Pickup(l)(db.WithContext(tctx))(tctx)(testProducer(&pickupMessages))(testSlotMaxLookup(100))(esc)(c.Id(), 1302000, 1, 0)
+	if err != nil {
+		t.Fatalf("Failed to pick up equipable: %v", err)
+	}
+	if len(supplied) != 1 || supplied[0] != 1302000 {
+		t.Fatalf("Expected the supplied statistics to be used, got: %v", supplied)
+	}
+	inv, err := inventory.GetInventories(l)(db.WithContext(tctx))(tctx)(c.Id())
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	if len(inv.Equipable().Items()) != 1 || inv.Equipable().Items()[0].ItemId() != 1302000 {
+		t.Fatalf("Expected equipable to be in inventory.")
+	}
+
+	err = inventory.Pickup(l)(db.WithContext(tctx))(tctx)(testProducer(&pickupMessages))(testSlotMaxLookup(100))(esc)(c.Id(), 4000000, 5, 0)
+	if err != nil {
+		t.Fatalf("Failed to pick up item: %v", err)
+	}
+	if len(supplied) != 1 {
+		t.Fatalf("Statistics supplied for a non-equipable.")
+	}
+	invId, err := inventory.GetInventoryIdByType(db.WithContext(tctx))(tctx)(c.Id(), inventory.TypeValueETC)()
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	i, err := item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 1)
+	if err != nil || !validateItem(i, ItemIdItemValidator(4000000), QuantityItemValidator(5)) {
+		t.Fatalf("Item failed validation.")
+	}
+	if len(pickupMessages) != 2 {
+		t.Fatalf("Expected an add event per pickup, got: %v", pickupMessages)
+	}
+}
+
+func TestPickupExistingEquipment(t *testing.T) {
+	t.Setenv("EQUIPABLE_STATISTICS_STORE", statistics2.StoreLocal)
+	l := testLogger()
+	db := testDatabase(t)
+	if err := statistics2.Migration(db); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	tt := testTenant()
+	tctx := tenant.WithContext(context.Background(), tt)
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).SetExperience(0).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+	equipId, err := inventory.GetInventoryIdByType(db.WithContext(tctx))(tctx)(c.Id(), inventory.TypeValueEquip)()
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	referenceId := uint32(12)
+	seedLocalEquipable(t, db, tt, equipId, 1302000, 1, referenceId)
+
+	// A pickup of equipment which was never dropped must not duplicate it.
+	var pickupMessages = make([]kafka.Message, 0)
+	err = inventory.Pickup(l)(db.WithContext(tctx))(tctx)(testProducer(&pickupMessages))(testSlotMaxLookup(100))(statistics2.Existing(l)(tctx)(referenceId))(c.Id(), 1302000, 1, referenceId)
+	if !errors.Is(err, inventory.ErrEquipmentExists) {
+		t.Fatalf("Expected equipment to exist, got: %v", err)
+	}
+	if len(pickupMessages) != 0 {
+		t.Fatalf("No events should be emitted, was %d", len(pickupMessages))
+	}
+	inv, err := inventory.GetInventories(l)(db.WithContext(tctx))(tctx)(c.Id())
+	if err != nil || len(inv.Equipable().Items()) != 1 {
+		t.Fatalf("Expected a single equipable, got: %v", inv.Equipable().Items())
+	}
+}
+
+func TestDrop(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).SetExperience(0).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	err = createMockItemAsset(l)(db.WithContext(tctx))(tctx)(c.Id())(4)(4000000)(10)
+	if err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+
+	pp := func(characterId uint32) model.Provider[inventory.Position] {
+		return model.FixedProvider(inventory.Position{X: 120, Y: -45})
+	}
+	f := inventory.Field{WorldId: 1, ChannelId: 2, MapId: 100000000}
+	var dropMessages = make([]kafka.Message, 0)
+	err = inventory.Drop(l)(db.WithContext(tctx))(tctx)(testProducer(&dropMessages))(pp)(f)(4)(c.Id())(1)(3)
+	if err != nil {
+		t.Fatalf("Failed to drop item: %v", err)
+	}
+	if len(dropMessages) != 2 {
+		t.Fatalf("Expected an update and a dropped event, got: %v", dropMessages)
+	}
+
+	var dropped struct {
+		WorldId   byte   `json:"worldId"`
+		ChannelId byte   `json:"channelId"`
+		MapId     uint32 `json:"mapId"`
+		Type      string `json:"type"`
+		Body      struct {
+			ItemId   uint32 `json:"itemId"`
+			Quantity uint32 `json:"quantity"`
+			X        int16  `json:"x"`
+			Y        int16  `json:"y"`
+		} `json:"body"`
+	}
+	err = json.Unmarshal(dropMessages[1].Value, &dropped)
+	if err != nil {
+		t.Fatalf("Failed to decode dropped event: %v", err)
+	}
+	if dropped.Type != inventory.EventDropTypeDropped || dropped.WorldId != 1 || dropped.ChannelId != 2 || dropped.MapId != 100000000 {
+		t.Fatalf("Dropped event has unexpected location: %+v", dropped)
+	}
+	if dropped.Body.ItemId != 4000000 || dropped.Body.Quantity != 3 || dropped.Body.X != 120 || dropped.Body.Y != -45 {
+		t.Fatalf("Dropped event has unexpected item: %+v", dropped.Body)
+	}
+}
+
+func TestCrossTenantWrites(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	tctx := tenant.WithContext(context.Background(), testTenant())
+	octx := tenant.WithContext(context.Background(), testTenant())
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).SetExperience(0).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+	err = createMockItemAsset(l)(db.WithContext(tctx))(tctx)(c.Id())(2)(2000000)(10)
+	if err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+	invId, err := inventory.GetInventoryIdByType(db.WithContext(tctx))(tctx)(c.Id(), inventory.TypeValueUse)()
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	i, err := item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 1)
+	if err != nil {
+		t.Fatalf("Failed to get item: %v", err)
+	}
+
+	// Another tenant addressing the item by id must not alter it.
+	writes := map[string]error{
+		"update quantity": item.UpdateQuantity(db.WithContext(octx))(octx)(i.Id(), 99),
+		"update slot":     item.UpdateSlot(db.WithContext(octx))(octx)(i.Id(), 7),
+		"remove":          item.RemoveItem(db.WithContext(octx))(octx)(invId, i.Id()),
+		"delete":          item.DeleteById(db.WithContext(octx))(octx)(i.Id()),
+	}
+	for name, err := range writes {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("Expected %s by another tenant to find nothing, got: %v", name, err)
+		}
+	}
+
+	i, err = item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 1)
+	if err != nil {
+		t.Fatalf("Item was moved or deleted by another tenant: %v", err)
+	}
+	if !validateItem(i, ItemIdItemValidator(2000000), QuantityItemValidator(10)) {
+		t.Fatalf("Item was modified by another tenant.")
+	}
+}
+
+func TestSlotUniqueness(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).SetExperience(0).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	invId, err := inventory.GetInventoryIdByType(db.WithContext(tctx))(tctx)(c.Id(), inventory.TypeValueUse)()
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	_, err = item.CreateItemInSlot(db.WithContext(tctx))(tctx)(invId)(2000000, 10, 1)
+	if err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+	_, err = item.CreateItemInSlot(db.WithContext(tctx))(tctx)(invId)(2000001, 10, 1)
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("Expected duplicated key, got: %v", err)
+	}
+
+	_, err = item.CreateItemInSlot(db.WithContext(tctx))(tctx)(invId)(2000001, 10, 2)
+	if err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+	var moveMessages = make([]kafka.Message, 0)
+	err = inventory.Move(l)(db.WithContext(tctx))(tctx)(testProducer(&moveMessages))(testSlotMaxLookup(100))(2)(c.Id())(1)(2)
+	if err != nil {
+		t.Fatalf("Failed to swap items: %v", err)
+	}
+	i1, err := item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 1)
+	if err != nil || !validateItem(i1, ItemIdItemValidator(2000001)) {
+		t.Fatalf("Item failed validation.")
+	}
+	i2, err := item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 2)
+	if err != nil || !validateItem(i2, ItemIdItemValidator(2000000)) {
+		t.Fatalf("Item failed validation.")
+	}
+}
+
+func TestSwapFailure(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	tctx := tenant.WithContext(context.Background(), testTenant())
+
+	var createMessages = make([]kafka.Message, 0)
+	input := character.NewModelBuilder().SetAccountId(1000).SetWorldId(0).SetName("Atlas").SetLevel(1).SetExperience(0).Build()
+	c, err := character.Create(l)(db.WithContext(tctx))(tctx)(testProducer(&createMessages))(input)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+
+	// A stranded item in the temporary slot prevents the occupant of the destination being parked.
+	invId, err := inventory.GetInventoryIdByType(db.WithContext(tctx))(tctx)(c.Id(), inventory.TypeValueUse)()
+	if err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	for s, itemId := range map[int16]uint32{1: 2000000, 2: 2000001, math.MinInt16: 2000002} {
+		_, err = item.CreateItemInSlot(db.WithContext(tctx))(tctx)(invId)(itemId, 10, s)
+		if err != nil {
+			t.Fatalf("Failed to create item: %v", err)
+		}
+	}
+	var moveMessages = make([]kafka.Message, 0)
+	err = inventory.Move(l)(db.WithContext(tctx))(tctx)(testProducer(&moveMessages))(testSlotMaxLookup(100))(2)(c.Id())(1)(2)
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("Expected swap to fail with duplicated key, got: %v", err)
+	}
+	if len(moveMessages) != 0 {
+		t.Fatalf("Expected no events for a failed swap, got: %v", moveMessages)
+	}
+	for s, itemId := range map[int16]uint32{1: 2000000, 2: 2000001} {
+		i, err := item.GetBySlot(db.WithContext(tctx))(tctx)(invId, s)
+		if err != nil || !validateItem(i, ItemIdItemValidator(itemId)) {
+			t.Fatalf("Item in slot [%d] failed validation.", s)
+		}
+	}
+
+	// Sorting parks each stack at an offset from the temporary slot, which collides in the same way.
+	var sortMessages = make([]kafka.Message, 0)
+	err = inventory.Sort(l)(db.WithContext(tctx))(tctx)(testProducer(&sortMessages))(testSlotMaxLookup(100))(c.Id(), inventory.TypeValueUse)
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("Expected sort to fail with duplicated key, got: %v", err)
+	}
+	if len(sortMessages) != 0 {
+		t.Fatalf("Expected no events for a failed sort, got: %v", sortMessages)
+	}
+	i, err := item.GetBySlot(db.WithContext(tctx))(tctx)(invId, 1)
+	if err != nil || !validateItem(i, ItemIdItemValidator(2000000)) {
+		t.Fatalf("Item in slot [%d] failed validation.", 1)
+	}
+
+	// Equipping into an occupied slot parks the worn equipment the same way.
+	top := createAndVerifyMockEquip(t)(l)(db.WithContext(tctx))(tctx)(c.Id())(1040010)
+	other := createAndVerifyMockEquip(t)(l)(db.WithContext(tctx))(tctx)(c.Id())(1040002)
+	stranded := createAndVerifyMockEquip(t)(l)(db.WithContext(tctx))(tctx)(c.Id())(1040000)
+	var equipMessages = make([]kafka.Message, 0)
+	equipFunc := inventory.EquipItemForCharacter(l)(db.WithContext(tctx))(tctx)(model.Flip(equipable.GetNextFreeSlot(l)(inventory.GetCapacity))(tctx))(equipment.WeaponTypeTwoHandedProvider)(testProducer(&equipMessages))(c.Id())
+	err = equipFunc(top.Slot())(equipment.FixedDestinationProvider(int16(slot.PositionTop)))
+	if err != nil {
+		t.Fatalf("Failed to equip top: %v", err)
+	}
+	err = equipable.UpdateSlot(db.WithContext(tctx))(tctx)(stranded.Id(), math.MinInt16)
+	if err != nil {
+		t.Fatalf("Failed to strand equipment: %v", err)
+	}
+	err = equipFunc(other.Slot())(equipment.FixedDestinationProvider(int16(slot.PositionTop)))
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("Expected equip to fail with duplicated key, got: %v", err)
+	}
+	worn, err := equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), int16(slot.PositionTop))
+	if err != nil || !validateEquipable(worn, EquipableItemIdValidator(1040010)) {
+		t.Fatalf("Worn top failed validation.")
+	}
+	e, err := equipable.GetBySlot(db.WithContext(tctx))(tctx)(c.Id(), other.Slot())
+	if err != nil || !validateEquipable(e, EquipableItemIdValidator(1040002)) {
+		t.Fatalf("Unequipped top failed validation.")
+	}
+}
+
+type statusError int
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("status %d", int(e))
+}
+
+func (e statusError) StatusCode() int {
+	return int(e)
+}
+
+func testItemLookup(slotMax uint32, err error) inventory.ItemLookup {
+	return func(itemId uint32) (information.Model, error) {
+		if err != nil {
+			return information.Model{}, err
+		}
+		return information.Extract(information.RestModel{Id: itemId, SlotMax: slotMax})
+	}
+}
+
+func testSkillLookup(levels map[uint32]byte, err error) inventory.SkillLookup {
+	return func(characterId uint32, skillId uint32) (skill.Model, error) {
+		if err != nil {
+			return skill.Model{}, err
+		}
+		level, ok := levels[skillId]
+		if !ok {
+			return skill.Model{}, statusError(http.StatusNotFound)
+		}
+		return skill.Extract(skill.RestModel{Id: skillId, Level: level})
+	}
+}
+
+func TestSlotMaxLookup(t *testing.T) {
+	l := testLogger()
+	noSkills := testSkillLookup(map[uint32]byte{}, nil)
+
+	for _, tc := range []struct {
+		name     string
+		itemId   uint32
+		items    inventory.ItemLookup
+		skills   inventory.SkillLookup
+		expected uint32
+	}{
+		{"given by game data", 2000000, testItemLookup(200, nil), noSkills, 200},
+		{"default", 2000000, testItemLookup(0, nil), noSkills, 100},
+		{"unlearned mastery", 2070000, testItemLookup(500, nil), noSkills, 500},
+		{"claw mastery", 2070000, testItemLookup(500, nil), testSkillLookup(map[uint32]byte{skill.AssassinClawMastery: 10}, nil), 600},
+		{"gun mastery", 2330000, testItemLookup(500, nil), testSkillLookup(map[uint32]byte{skill.GunslingerGunMastery: 5}, nil), 550},
+		{"mastery of other items", 2000000, testItemLookup(200, nil), testSkillLookup(map[uint32]byte{skill.AssassinClawMastery: 10}, nil), 200},
+	} {
+		sm, err := inventory.SlotMaxLookupOf(l)(tc.items, tc.skills)(1)(tc.itemId)()
+		if err != nil {
+			t.Fatalf("%s: failed to determine slot max: %v", tc.name, err)
+		}
+		if sm != tc.expected {
+			t.Fatalf("%s: slot max should be %d, was %d.", tc.name, tc.expected, sm)
+		}
+	}
+}
+
+func TestSlotMaxLookupFailure(t *testing.T) {
+	l := testLogger()
+	unavailable := statusError(http.StatusServiceUnavailable)
+
+	_, err := inventory.SlotMaxLookupOf(l)(testItemLookup(0, unavailable), testSkillLookup(map[uint32]byte{}, nil))(1)(2000000)()
+	if !errors.Is(err, unavailable) {
+		t.Fatalf("Expected the item lookup failure, got %v.", err)
+	}
+	_, err = inventory.SlotMaxLookupOf(l)(testItemLookup(500, nil), testSkillLookup(nil, unavailable))(1)(2070000)()
+	if !errors.Is(err, unavailable) {
+		t.Fatalf("Expected the skill lookup failure, got %v.", err)
+	}
+}
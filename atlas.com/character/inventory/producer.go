@@ -1,6 +1,9 @@
 package inventory
 
 import (
+	"atlas-character/equipable/statistics"
+	"atlas-character/equipment"
+	"errors"
 	"github.com/Chronicle20/atlas-kafka/producer"
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/segmentio/kafka-go"
@@ -93,6 +96,12 @@ func inventoryItemMoveProvider(characterId uint32) func(oldSlot int16) func(item
 	}
 }
 
+// ItemMovedEventProvider conveys the move of an asset of a character from oldSlot to slot, for moves made outside of the
+// inventory commands.
+func ItemMovedEventProvider(characterId uint32) func(oldSlot int16) func(itemId uint32) func(slot int16) model.Provider[[]kafka.Message] {
+	return inventoryItemMoveProvider(characterId)
+}
+
 func inventoryItemRemoveProvider(characterId uint32, itemId uint32, slot int16) model.Provider[[]kafka.Message] {
 	key := producer.CreateKey(int(characterId))
 	value := &inventoryChangedEvent[inventoryChangedItemRemoveBody]{
@@ -105,3 +114,98 @@ func inventoryItemRemoveProvider(characterId uint32, itemId uint32, slot int16)
 	}
 	return producer.SingleMessageProvider(key, value)
 }
+
+func inventoryEquipRejectedProvider(characterId uint32, source int16, cause error) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	body := inventoryChangedEquipRejectedBody{Reason: EquipRejectedReasonUnknown}
+	var re equipment.RequirementError
+	if errors.As(cause, &re) {
+		body = inventoryChangedEquipRejectedBody{
+			ItemId:   re.ItemId,
+			Reason:   string(re.Reason),
+			Required: re.Required,
+			Actual:   re.Actual,
+		}
+	}
+	value := &inventoryChangedEvent[inventoryChangedEquipRejectedBody]{
+		CharacterId: characterId,
+		Slot:        source,
+		Type:        ChangedTypeEquipRejected,
+		Body:        body,
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func inventoryPickupRejectedProvider(characterId uint32, itemId uint32, quantity uint32, referenceId uint32, cause error) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	reason := PickupRejectedReasonUnknown
+	if errors.Is(cause, ErrInventoryFull) {
+		reason = PickupRejectedReasonInventoryFull
+	} else if errors.Is(cause, ErrEquipmentExists) {
+		reason = PickupRejectedReasonEquipmentExists
+	}
+	value := &inventoryChangedEvent[inventoryChangedPickupRejectedBody]{
+		CharacterId: characterId,
+		Type:        ChangedTypePickupRejected,
+		Body: inventoryChangedPickupRejectedBody{
+			ItemId:      itemId,
+			Quantity:    quantity,
+			ReferenceId: referenceId,
+			Reason:      reason,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func inventoryCapacityChangedProvider(characterId uint32, inventoryType Type, capacity uint32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &inventoryChangedEvent[inventoryChangedCapacityBody]{
+		CharacterId: characterId,
+		Type:        ChangedTypeCapacity,
+		Body: inventoryChangedCapacityBody{
+			InventoryType: byte(inventoryType),
+			Capacity:      capacity,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func itemDroppedEventProvider(characterId uint32, field Field, position Position, itemId uint32, quantity uint32, referenceId uint32, sm *statistics.Model) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	body := dropEventDroppedBody{
+		ItemId:      itemId,
+		Quantity:    quantity,
+		X:           position.X,
+		Y:           position.Y,
+		ReferenceId: referenceId,
+	}
+	if sm != nil {
+		body.Statistics = &dropEventStatisticsBody{
+			Strength:      sm.Strength(),
+			Dexterity:     sm.Dexterity(),
+			Intelligence:  sm.Intelligence(),
+			Luck:          sm.Luck(),
+			HP:            sm.HP(),
+			MP:            sm.MP(),
+			WeaponAttack:  sm.WeaponAttack(),
+			MagicAttack:   sm.MagicAttack(),
+			WeaponDefense: sm.WeaponDefense(),
+			MagicDefense:  sm.MagicDefense(),
+			Accuracy:      sm.Accuracy(),
+			Avoidability:  sm.Avoidability(),
+			Hands:         sm.Hands(),
+			Speed:         sm.Speed(),
+			Jump:          sm.Jump(),
+			Slots:         sm.Slots(),
+		}
+	}
+	value := &dropEvent[dropEventDroppedBody]{
+		WorldId:     field.WorldId,
+		ChannelId:   field.ChannelId,
+		MapId:       field.MapId,
+		CharacterId: characterId,
+		Type:        EventDropTypeDropped,
+		Body:        body,
+	}
+	return producer.SingleMessageProvider(key, value)
+}
@@ -13,6 +13,12 @@ func get(tenantId uuid.UUID, characterId uint32, inventoryType Type) database.En
 	}
 }
 
+func getById(tenantId uuid.UUID, id uint32) database.EntityProvider[entity] {
+	return func(db *gorm.DB) model.Provider[entity] {
+		return database.Query[entity](db, &entity{TenantId: tenantId, ID: id})
+	}
+}
+
 func getByCharacter(tenantId uuid.UUID, characterId uint32) database.EntityProvider[[]entity] {
 	return func(db *gorm.DB) model.Provider[[]entity] {
 		return database.SliceQuery[entity](db, &entity{TenantId: tenantId, CharacterId: characterId})
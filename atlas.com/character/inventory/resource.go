@@ -6,6 +6,7 @@ import (
 	"atlas-character/inventory/item"
 	"atlas-character/kafka/producer"
 	"atlas-character/rest"
+	"errors"
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/Chronicle20/atlas-rest/server"
 	"github.com/gorilla/mux"
@@ -21,6 +22,7 @@ const (
 	EquipItem         = "equip_item"
 	UnequipItem       = "unequip_item"
 	getItemBySlot     = "get_item_by_slot"
+	expandInventory   = "expand_inventory"
 )
 
 func InitResource(si jsonapi.ServerInformation) func(db *gorm.DB) server.RouteInitializer {
@@ -31,6 +33,7 @@ func InitResource(si jsonapi.ServerInformation) func(db *gorm.DB) server.RouteIn
 			r := router.PathPrefix("/characters/{characterId}/inventories").Subrouter()
 			r.HandleFunc("/{inventoryType}/items", rest.RegisterInputHandler[item.RestModel](l)(db)(si)(handlerCreateItem, handleCreateItem)).Methods(http.MethodPost)
 			r.HandleFunc("/{inventoryType}/items", register(getItemBySlot, handleGetItemBySlot)).Methods(http.MethodGet).Queries("slot", "{slot}")
+			r.HandleFunc("/{inventoryType}/expansions", register(expandInventory, handleExpandInventory)).Methods(http.MethodPost)
 
 			er := router.PathPrefix("/characters/{characterId}/equipment").Subrouter()
 			er.HandleFunc("/{slotType}/equipable", rest.RegisterInputHandler[equipable.RestModel](l)(db)(si)(EquipItem, handleEquipItem)).Methods(http.MethodPost)
@@ -116,7 +119,36 @@ func handleCreateItem(d *rest.HandlerDependency, _ *rest.HandlerContext, model i
 		return rest.ParseInventoryType(d.Logger(), func(inventoryType int8) http.HandlerFunc {
 			return func(w http.ResponseWriter, r *http.Request) {
 				err := CreateItem(d.Logger())(d.DB())(d.Context())(producer.ProviderImpl(d.Logger())(d.Context()))(characterId, Type(inventoryType), model.ItemId, model.Quantity)
+				if errors.Is(err, ErrInventoryFull) {
+					w.WriteHeader(http.StatusConflict)
+					return
+				}
+				if err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+		})
+	})
+}
+
+func handleExpandInventory(d *rest.HandlerDependency, _ *rest.HandlerContext) http.HandlerFunc {
+	return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+		return rest.ParseInventoryType(d.Logger(), func(inventoryType int8) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				err := Expand(d.Logger())(d.DB())(d.Context())(producer.ProviderImpl(d.Logger())(d.Context()))(characterId, Type(inventoryType), maxCapacity(d.Context()))
+				if errors.Is(err, ErrMaxCapacity) {
+					w.WriteHeader(http.StatusConflict)
+					return
+				}
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
 				if err != nil {
+					d.Logger().WithError(err).Errorf("Unable to expand inventory [%d] for character [%d].", inventoryType, characterId)
 					w.WriteHeader(http.StatusInternalServerError)
 					return
 				}
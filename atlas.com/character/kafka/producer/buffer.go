@@ -0,0 +1,48 @@
+package producer
+
+import (
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+)
+
+type buffered struct {
+	token    string
+	messages []kafka.Message
+}
+
+// Buffer holds the messages produced through it, so that work done within a transaction only announces itself once the
+// transaction commits.
+type Buffer struct {
+	produced []buffered
+}
+
+func NewBuffer() *Buffer {
+	return &Buffer{produced: make([]buffered, 0)}
+}
+
+// Provider produces into the buffer. Messages whose provider fails are not buffered, and the failure is returned.
+func (b *Buffer) Provider() Provider {
+	return func(token string) producer.MessageProducer {
+		return func(provider model.Provider[[]kafka.Message]) error {
+			ms, err := provider()
+			if err != nil {
+				return err
+			}
+			b.produced = append(b.produced, buffered{token: token, messages: ms})
+			return nil
+		}
+	}
+}
+
+// Flush produces the buffered messages through p, in the order they were buffered.
+func (b *Buffer) Flush(p Provider) error {
+	for _, bm := range b.produced {
+		err := p(bm.token)(model.FixedProvider(bm.messages))
+		if err != nil {
+			return err
+		}
+	}
+	b.produced = make([]buffered, 0)
+	return nil
+}
@@ -2,16 +2,25 @@ package main
 
 import (
 	"atlas-character/character"
+	"atlas-character/character/name"
+	"atlas-character/configuration"
+	"atlas-character/consistency"
 	"atlas-character/database"
-	"atlas-character/equipable"
+	"atlas-character/gamedata"
 	"atlas-character/inventory"
-	"atlas-character/inventory/item"
 	"atlas-character/logger"
+	"atlas-character/rest"
 	"atlas-character/service"
 	"atlas-character/session"
 	"atlas-character/tracing"
+	"context"
+	"flag"
 	"github.com/Chronicle20/atlas-kafka/consumer"
 	"github.com/Chronicle20/atlas-rest/server"
+	"github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"os"
 )
 import _ "net/http/pprof"
 
@@ -40,6 +49,14 @@ func GetServer() Server {
 
 func main() {
 	l := logger.CreateLogger(serviceName)
+	if len(os.Args) > 1 && os.Args[1] == consistency.CommandName {
+		runConsistencyCheck(l, os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == migrateCommand {
+		runMigrate(l, os.Args[2:])
+		return
+	}
 	l.Infoln("Starting main service.")
 
 	tdm := service.GetTeardownManager()
@@ -49,28 +66,116 @@ func main() {
 		l.WithError(err).Fatal("Unable to initialize tracer.")
 	}
 
-	db := database.Connect(l, database.SetMigrations(character.Migration, inventory.Migration, item.Migration, equipable.Migration))
+	registerNamePolicies(l)
+	warmGameData(l)
+
+	db := database.Connect(l, database.SetMigrations(migrations()...))
 
 	cm := consumer.GetManager()
 	cm.AddConsumer(l, tdm.Context(), tdm.WaitGroup())(inventory.EquipItemCommandConsumer(l)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser))
 	cm.AddConsumer(l, tdm.Context(), tdm.WaitGroup())(inventory.UnequipItemCommandConsumer(l)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser))
 	cm.AddConsumer(l, tdm.Context(), tdm.WaitGroup())(inventory.MoveItemCommandConsumer(l)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser))
+	cm.AddConsumer(l, tdm.Context(), tdm.WaitGroup())(inventory.SplitItemCommandConsumer(l)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser))
+	cm.AddConsumer(l, tdm.Context(), tdm.WaitGroup())(inventory.SortCommandConsumer(l)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser))
+	cm.AddConsumer(l, tdm.Context(), tdm.WaitGroup())(inventory.GatherCommandConsumer(l)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser))
 	cm.AddConsumer(l, tdm.Context(), tdm.WaitGroup())(inventory.DropItemCommandConsumer(l)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser))
+	cm.AddConsumer(l, tdm.Context(), tdm.WaitGroup())(inventory.PickupItemCommandConsumer(l)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser))
+	cm.AddConsumer(l, tdm.Context(), tdm.WaitGroup())(inventory.ExpandCommandConsumer(l)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser))
+	cm.AddConsumer(l, tdm.Context(), tdm.WaitGroup())(inventory.ConsumeItemCommandConsumer(l)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser))
 	cm.AddConsumer(l, tdm.Context(), tdm.WaitGroup())(session.StatusEventConsumer(l)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser))
 	cm.AddConsumer(l, tdm.Context(), tdm.WaitGroup())(character.CommandConsumer(l)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser))
 	cm.AddConsumer(l, tdm.Context(), tdm.WaitGroup())(character.MovementEventConsumer(l)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser))
-	_, _ = cm.RegisterHandler(inventory.EquipItemRegister(l, db))
+	_, _ = cm.RegisterHandler(inventory.EquipItemRegister(l, db, character.WearerProvider, inventory.ConfiguredAvailableSlots))
 	_, _ = cm.RegisterHandler(inventory.UnequipItemRegister(l, db))
 	_, _ = cm.RegisterHandler(inventory.MoveItemRegister(l, db))
-	_, _ = cm.RegisterHandler(inventory.DropItemRegister(l, db))
+	_, _ = cm.RegisterHandler(inventory.SplitItemRegister(l, db))
+	_, _ = cm.RegisterHandler(inventory.SortRegister(l, db))
+	_, _ = cm.RegisterHandler(inventory.GatherRegister(l, db))
+	_, _ = cm.RegisterHandler(inventory.DropItemRegister(l, db, character.PositionProvider))
+	_, _ = cm.RegisterHandler(inventory.PickupItemRegister(l, db))
+	_, _ = cm.RegisterHandler(inventory.ExpandRegister(l, db))
+	_, _ = cm.RegisterHandler(inventory.ConsumeItemRegister(l, db))
 	_, _ = cm.RegisterHandler(session.StatusEventRegister(l, db))
 	_, _ = cm.RegisterHandler(character.ChangeMapCommandRegister(l, db))
 	_, _ = cm.RegisterHandler(character.MovementEventRegister(l))
 
-	server.CreateService(l, tdm.Context(), tdm.WaitGroup(), GetServer().GetPrefix(), character.InitResource(GetServer())(db), inventory.InitResource(GetServer())(db))
+	server.CreateService(l, tdm.Context(), tdm.WaitGroup(), GetServer().GetPrefix(), character.InitResource(GetServer())(db), inventory.InitResource(GetServer())(db), consistency.InitResource(GetServer())(db), rest.InitClientMetricsResource)
 
 	tdm.TeardownFunc(tracing.Teardown(l)(tc))
 
 	tdm.Wait()
 	l.Infoln("Service shutdown.")
 }
+
+// runConsistencyCheck checks a tenant's inventories once and exits. Migrations are not run, so that data predating the
+// inventory constraints can be repaired before they are added.
+func runConsistencyCheck(l *logrus.Logger, args []string) {
+	l.SetOutput(os.Stderr)
+	db := database.Connect(l)
+	err := consistency.RunCommand(l, db, args, os.Stdout)
+	if err != nil {
+		l.WithError(err).Fatal("Unable to check inventories.")
+	}
+}
+
+const migrateCommand = "migrate"
+
+// runMigrate applies the pending schema migrations and exits. With -dry-run the pending migrations are only listed.
+func runMigrate(l *logrus.Logger, args []string) {
+	fs := flag.NewFlagSet(migrateCommand, flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "list pending migrations without applying them")
+	_ = fs.Parse(args)
+
+	db := database.Connect(l)
+	ms, err := database.Migrate(l)(db)(migrations()...)(*dryRun)
+	if err != nil {
+		l.WithError(err).Fatal("Unable to migrate schema.")
+	}
+	for _, m := range ms {
+		if *dryRun {
+			l.Infof("Pending migration [%d] %s.", m.Version(), m.Description())
+		} else {
+			l.Infof("Applied migration [%d] %s.", m.Version(), m.Description())
+		}
+	}
+	if len(ms) == 0 {
+		l.Infof("Schema is up to date.")
+	}
+}
+
+// warmGameData retrieves, in the background, the game data of every tenant whose version is configured.
+func warmGameData(l logrus.FieldLogger) {
+	for _, tc := range configuration.Get().Tenants {
+		if tc.Region == "" {
+			continue
+		}
+		tenantId, err := uuid.Parse(tc.Id)
+		if err != nil {
+			l.WithError(err).Fatalf("Invalid tenant id [%s] in configuration.", tc.Id)
+		}
+		t, err := tenant.Create(tenantId, tc.Region, tc.MajorVersion, tc.MinorVersion)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to warm game data for tenant [%s].", tc.Id)
+			continue
+		}
+		go gamedata.Warm(l)(tenant.WithContext(context.Background(), t))(character.TemplateWarmer(tc.Characters))
+	}
+}
+
+func registerNamePolicies(l logrus.FieldLogger) {
+	for _, tc := range configuration.Get().Tenants {
+		nc := tc.Characters.Names
+		if nc == nil {
+			continue
+		}
+		tenantId, err := uuid.Parse(tc.Id)
+		if err != nil {
+			l.WithError(err).Fatalf("Invalid tenant id [%s] in configuration.", tc.Id)
+		}
+		p, err := name.NewPolicy(nc.Pattern, nc.MinLength, nc.MaxLength, name.Measure(nc.Measure))
+		if err != nil {
+			l.WithError(err).Fatalf("Invalid character name rules for tenant [%s].", tc.Id)
+		}
+		name.GetRegistry().Add(tenantId, p)
+	}
+}
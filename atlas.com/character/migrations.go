@@ -0,0 +1,147 @@
+package main
+
+import (
+	"atlas-character/database"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// migrations are the versioned schema changes of the service, in the order they are applied. The first versions
+// create the tables as they stood when versioned migrations were introduced, and are no-ops on databases created
+// before then. They migrate the snapshots below rather than the entities, so later changes to an entity need a
+// migration of their own.
+func migrations() []database.Migration {
+	return []database.Migration{
+		database.GoMigration(1, "create characters", migrateBaseline(&baselineCharacter{}, "CREATE UNIQUE INDEX IF NOT EXISTS idx_characters_tenant_name ON characters (tenant_id, LOWER(name))")),
+		database.GoMigration(2, "create inventory", migrateBaseline(&baselineInventory{})),
+		database.GoMigration(3, "create items", migrateBaseline(&baselineItem{})),
+		database.GoMigration(4, "create equipables", migrateBaseline(&baselineEquipable{})),
+		database.GoMigration(5, "create equipable statistics", migrateBaseline(&baselineStatistics{})),
+		database.SQLMigration(6, "unique equipable references", "CREATE UNIQUE INDEX IF NOT EXISTS idx_equipables_tenant_reference ON equipables (tenant_id, reference_id) WHERE reference_id <> 0"),
+	}
+}
+
+// migrateBaseline creates the table of snapshot, followed by the statements in sql.
+func migrateBaseline(snapshot interface{}, sql ...string) func(db *gorm.DB) error {
+	return func(db *gorm.DB) error {
+		err := db.AutoMigrate(snapshot)
+		if err != nil {
+			return err
+		}
+		for _, s := range sql {
+			err = db.Exec(s).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// The snapshots below are the baseline schema. They must not change.
+
+type baselineCharacter struct {
+	TenantId           uuid.UUID `gorm:"not null"`
+	ID                 uint32    `gorm:"primaryKey;autoIncrement;not null"`
+	AccountId          uint32    `gorm:"not null"`
+	World              byte      `gorm:"not null"`
+	Name               string    `gorm:"not null"`
+	Level              byte      `gorm:"not null;default=1"`
+	Experience         uint32    `gorm:"not null;default=0"`
+	GachaponExperience uint32    `gorm:"not null;default=0"`
+	Strength           uint16    `gorm:"not null;default=12"`
+	Dexterity          uint16    `gorm:"not null;default=5"`
+	Intelligence       uint16    `gorm:"not null;default=4"`
+	Luck               uint16    `gorm:"not null;default=4"`
+	HP                 uint16    `gorm:"not null;default=50"`
+	MP                 uint16    `gorm:"not null;default=5"`
+	MaxHP              uint16    `gorm:"not null;default=50"`
+	MaxMP              uint16    `gorm:"not null;default=5"`
+	Meso               uint32    `gorm:"not null;default=0"`
+	HPMPUsed           int       `gorm:"not null;default=0"`
+	JobId              uint16    `gorm:"not null;default=0"`
+	SkinColor          byte      `gorm:"not null;default=0"`
+	Gender             byte      `gorm:"not null;default=0"`
+	Fame               int16     `gorm:"not null;default=0"`
+	Hair               uint32    `gorm:"not null;default=0"`
+	Face               uint32    `gorm:"not null;default=0"`
+	AP                 uint16    `gorm:"not null;default=0"`
+	SP                 string    `gorm:"not null;default=0,0,0,0,0,0,0,0,0,0"`
+	MapId              uint32    `gorm:"not null;default=0"`
+	SpawnPoint         uint32    `gorm:"not null;default=0"`
+	GM                 int       `gorm:"not null;default=0"`
+	X                  int16     `gorm:"not null;default=0"`
+	Y                  int16     `gorm:"not null;default=0"`
+	Stance             byte      `gorm:"not null;default=0"`
+}
+
+func (e baselineCharacter) TableName() string {
+	return "characters"
+}
+
+type baselineInventory struct {
+	TenantId      uuid.UUID         `gorm:"not null;uniqueIndex:idx_inventory_character_type"`
+	ID            uint32            `gorm:"primaryKey;autoIncrement;not null"`
+	CharacterId   uint32            `gorm:"not null;uniqueIndex:idx_inventory_character_type;index"`
+	InventoryType int8              `gorm:"not null;uniqueIndex:idx_inventory_character_type"`
+	Capacity      uint32            `gorm:"capacity"`
+	Character     baselineCharacter `gorm:"foreignKey:CharacterId;constraint:OnDelete:CASCADE"`
+}
+
+func (e baselineInventory) TableName() string {
+	return "inventory"
+}
+
+type baselineItem struct {
+	TenantId    uuid.UUID         `gorm:"not null"`
+	ID          uint32            `gorm:"primaryKey;autoIncrement;not null"`
+	InventoryId uint32            `gorm:"not null;uniqueIndex:idx_items_inventory_slot"`
+	ItemId      uint32            `gorm:"not null"`
+	Slot        int16             `gorm:"not null;uniqueIndex:idx_items_inventory_slot"`
+	Quantity    uint32            `gorm:"not null"`
+	Inventory   baselineInventory `gorm:"foreignKey:InventoryId;constraint:OnDelete:CASCADE"`
+}
+
+func (e baselineItem) TableName() string {
+	return "items"
+}
+
+type baselineEquipable struct {
+	TenantId    uuid.UUID         `gorm:"not null"`
+	ID          uint32            `gorm:"primaryKey;autoIncrement;not null"`
+	InventoryId uint32            `gorm:"not null;uniqueIndex:idx_equipables_inventory_slot"`
+	ItemId      uint32            `gorm:"not null"`
+	Slot        int16             `gorm:"not null;uniqueIndex:idx_equipables_inventory_slot"`
+	ReferenceId uint32            `gorm:"not null"`
+	Inventory   baselineInventory `gorm:"foreignKey:InventoryId;constraint:OnDelete:CASCADE"`
+}
+
+func (e baselineEquipable) TableName() string {
+	return "equipables"
+}
+
+type baselineStatistics struct {
+	TenantId      uuid.UUID `gorm:"not null"`
+	ID            uint32    `gorm:"primaryKey;autoIncrement;not null"`
+	ItemId        uint32    `gorm:"not null"`
+	Strength      uint16    `gorm:"not null;default=0"`
+	Dexterity     uint16    `gorm:"not null;default=0"`
+	Intelligence  uint16    `gorm:"not null;default=0"`
+	Luck          uint16    `gorm:"not null;default=0"`
+	HP            uint16    `gorm:"not null;default=0"`
+	MP            uint16    `gorm:"not null;default=0"`
+	WeaponAttack  uint16    `gorm:"not null;default=0"`
+	MagicAttack   uint16    `gorm:"not null;default=0"`
+	WeaponDefense uint16    `gorm:"not null;default=0"`
+	MagicDefense  uint16    `gorm:"not null;default=0"`
+	Accuracy      uint16    `gorm:"not null;default=0"`
+	Avoidability  uint16    `gorm:"not null;default=0"`
+	Hands         uint16    `gorm:"not null;default=0"`
+	Speed         uint16    `gorm:"not null;default=0"`
+	Jump          uint16    `gorm:"not null;default=0"`
+	Slots         uint16    `gorm:"not null;default=0"`
+}
+
+func (e baselineStatistics) TableName() string {
+	return "equipable_statistics"
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"atlas-character/database"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestMigrations(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	applied, err := database.Migrate(l)(db)(migrations()...)(false)
+	if err != nil || len(applied) != len(migrations()) {
+		t.Fatalf("Expected every migration to be applied, got: %v %v", applied, err)
+	}
+	for table, indexes := range map[string][]string{
+		"characters":           {"idx_characters_tenant_name"},
+		"inventory":            {"idx_inventory_character_type"},
+		"items":                {"idx_items_inventory_slot"},
+		"equipables":           {"idx_equipables_inventory_slot", "idx_equipables_tenant_reference"},
+		"equipable_statistics": {},
+	} {
+		if !db.Migrator().HasTable(table) {
+			t.Fatalf("Expected table [%s] to be created.", table)
+		}
+		for _, index := range indexes {
+			if !db.Migrator().HasIndex(table, index) {
+				t.Fatalf("Expected index [%s] on table [%s].", index, table)
+			}
+		}
+	}
+}
@@ -1,15 +1,23 @@
 package portal
 
 import (
+	"atlas-character/gamedata"
 	"context"
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/Chronicle20/atlas-rest/requests"
 	"github.com/sirupsen/logrus"
 )
 
+type portalKey struct {
+	mapId uint32
+	id    uint32
+}
+
+var cache = gamedata.NewCache[portalKey, Model]()
+
 func inMapByIdModelProvider(l logrus.FieldLogger, ctx context.Context) func(mapId uint32, id uint32) model.Provider[Model] {
 	return func(mapId uint32, id uint32) model.Provider[Model] {
-		return requests.Provider[RestModel, Model](l, ctx)(requestInMapById(mapId, id), Extract)
+		return cache.Provider(ctx, portalKey{mapId: mapId, id: id}, requests.Provider[RestModel, Model](l, ctx)(requestInMapById(mapId, id), Extract))
 	}
 }
 
@@ -0,0 +1,247 @@
+package rest
+
+import (
+	"atlas-character/retry"
+	"context"
+	"errors"
+	"github.com/Chronicle20/atlas-rest/requests"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrTimeout = errors.New("request timed out")
+
+// dependencies are the services called, by the prefix of the environment variables configuring them. A dependency's
+// base URL is read from <prefix>_URL.
+var dependencies = []string{"GAME_DATA_SERVICE", "EQUIPABLE_SERVICE", "SKILL_SERVICE"}
+
+// Policy governs the calls made to a dependency. Timeout bounds every attempt. Idempotent calls are attempted up to
+// Attempts times, backing off exponentially from BaseDelay to at most MaxDelay with full jitter. After
+// FailureThreshold consecutive failures the dependency's breaker opens, failing calls immediately for Cooldown.
+type Policy struct {
+	Timeout          time.Duration
+	Attempts         int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	FailureThreshold int
+	Cooldown         time.Duration
+}
+
+// DefaultPolicy is overridden for every dependency by REST_CLIENT_<setting>, and for a single dependency by
+// <prefix>_<setting>, where the settings are TIMEOUT, ATTEMPTS, BASE_DELAY, MAX_DELAY, FAILURE_THRESHOLD and COOLDOWN.
+var DefaultPolicy = Policy{
+	Timeout:          5 * time.Second,
+	Attempts:         3,
+	BaseDelay:        100 * time.Millisecond,
+	MaxDelay:         2 * time.Second,
+	FailureThreshold: 5,
+	Cooldown:         30 * time.Second,
+}
+
+type dependency struct {
+	name    string
+	policy  Policy
+	breaker *breaker
+	metrics *metrics
+}
+
+type clientRegistry struct {
+	mutex        sync.Mutex
+	dependencies map[string]*dependency
+}
+
+var cr *clientRegistry
+var crOnce sync.Once
+
+func getClientRegistry() *clientRegistry {
+	crOnce.Do(func() {
+		cr = &clientRegistry{dependencies: make(map[string]*dependency)}
+	})
+	return cr
+}
+
+// dependencyFor resolves the dependency a URL belongs to. URLs of unknown services are keyed by their scheme and host,
+// and follow the default policy.
+func (r *clientRegistry) dependencyFor(rawUrl string) *dependency {
+	name, baseUrl, prefix := "", "", ""
+	for _, p := range dependencies {
+		if b := os.Getenv(p + "_URL"); b != "" && strings.HasPrefix(rawUrl, b) {
+			name, baseUrl, prefix = strings.ToLower(p), b, p
+			break
+		}
+	}
+	if baseUrl == "" {
+		baseUrl = rawUrl
+		if u, err := url.Parse(rawUrl); err == nil {
+			baseUrl = u.Scheme + "://" + u.Host
+		}
+		name = baseUrl
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if d, ok := r.dependencies[baseUrl]; ok {
+		return d
+	}
+	p := policyFromEnvironment(policyFromEnvironment(DefaultPolicy, "REST_CLIENT"), prefix)
+	d := &dependency{name: name, policy: p, breaker: newBreaker(p.FailureThreshold, p.Cooldown), metrics: &metrics{}}
+	r.dependencies[baseUrl] = d
+	return d
+}
+
+func policyFromEnvironment(p Policy, prefix string) Policy {
+	if prefix == "" {
+		return p
+	}
+	duration := func(key string, fallback time.Duration) time.Duration {
+		if d, err := time.ParseDuration(os.Getenv(prefix + "_" + key)); err == nil {
+			return d
+		}
+		return fallback
+	}
+	integer := func(key string, fallback int) int {
+		if i, err := strconv.Atoi(os.Getenv(prefix + "_" + key)); err == nil {
+			return i
+		}
+		return fallback
+	}
+	return Policy{
+		Timeout:          duration("TIMEOUT", p.Timeout),
+		Attempts:         max(integer("ATTEMPTS", p.Attempts), 1),
+		BaseDelay:        duration("BASE_DELAY", p.BaseDelay),
+		MaxDelay:         duration("MAX_DELAY", p.MaxDelay),
+		FailureThreshold: integer("FAILURE_THRESHOLD", p.FailureThreshold),
+		Cooldown:         duration("COOLDOWN", p.Cooldown),
+	}
+}
+
+// Resilient decorates a request to rawUrl with its dependency's policy. Only idempotent requests are retried, and the
+// error of their last attempt is wrapped in retry.ErrExhausted once the attempts are used up. Only transient failures
+// are retried and count towards the breaker; any other error, such as a client error, is returned as is. A request
+// cancelled or timed out by its caller's context is neither retried nor counted for or against the dependency.
+func Resilient[A any](rawUrl string, idempotent bool, r requests.Request[A]) requests.Request[A] {
+	return func(l logrus.FieldLogger, ctx context.Context) (A, error) {
+		d := getClientRegistry().dependencyFor(rawUrl)
+		attempts := 1
+		if idempotent {
+			attempts = d.policy.Attempts
+		}
+
+		var res A
+		p := retry.Jittered(retry.Exponential(d.policy.BaseDelay, d.policy.MaxDelay, attempts))
+		err := retry.Try(ctx, p, func(attempt int) (bool, error) {
+			if err := d.breaker.allow(); err != nil {
+				d.metrics.rejected()
+				return false, err
+			}
+			if attempt > 1 {
+				d.metrics.retried()
+			}
+			d.metrics.requested()
+			var err error
+			res, err = attemptWithTimeout(ctx, l, d.policy.Timeout, r)
+			if err != nil && ctx.Err() != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+				d.breaker.abandoned()
+				return false, err
+			}
+			if err == nil || !transient(err) {
+				// The dependency answered, so it is healthy even should it have refused the request.
+				d.breaker.succeeded()
+				return false, err
+			}
+			d.metrics.failed(errors.Is(err, ErrTimeout))
+			if d.breaker.failed() {
+				l.WithError(err).Warnf("Circuit to [%s] opened for [%s].", d.name, d.policy.Cooldown)
+			}
+			l.WithError(err).Debugf("Attempt [%d] of request to [%s] failed.", attempt, rawUrl)
+			return idempotent, err
+		})
+		return res, err
+	}
+}
+
+// ResilientDelete decorates a DELETE request to rawUrl with its dependency's policy, retrying it as idempotent. Should
+// a retried attempt find nothing to delete, an earlier attempt is taken to have deleted it without its response
+// arriving, and the request succeeds.
+func ResilientDelete(rawUrl string, r requests.EmptyBodyRequest) requests.EmptyBodyRequest {
+	return func(l logrus.FieldLogger, ctx context.Context) error {
+		var attempts atomic.Int32
+		_, err := Resilient(rawUrl, true, func(l logrus.FieldLogger, ctx context.Context) (struct{}, error) {
+			err := r(l, ctx)
+			if attempts.Add(1) > 1 && IsNotFound(err) {
+				l.Debugf("Retried delete of [%s] found nothing to delete.", rawUrl)
+				return struct{}{}, nil
+			}
+			return struct{}{}, err
+		})(l, ctx)
+		return err
+	}
+}
+
+// transient reports whether err may clear up on its own: a timeout, a failure to reach the dependency, or a server
+// error. Server errors are recognised by a StatusCode method on the error. A url.Error is judged by its cause, as one
+// is also returned for requests which could never succeed, such as those with an unsupported protocol scheme.
+func transient(err error) bool {
+	if errors.Is(err, ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		return errors.Is(ue.Err, io.EOF) || errors.Is(ue.Err, io.ErrUnexpectedEOF) || transient(ue.Err)
+	}
+	var ne net.Error
+	if errors.As(err, &ne) {
+		return true
+	}
+	var se interface{ StatusCode() int }
+	if errors.As(err, &se) {
+		return se.StatusCode() >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// IsNotFound reports whether err is the dependency answering that what was requested does not exist. As with server
+// errors, the status is recognised by a StatusCode method on the error.
+func IsNotFound(err error) bool {
+	var se interface{ StatusCode() int }
+	return errors.As(err, &se) && se.StatusCode() == http.StatusNotFound
+}
+
+// attemptWithTimeout bounds a request by timeout, returning ErrTimeout once it elapses, even should the request not
+// honor its context.
+func attemptWithTimeout[A any](ctx context.Context, l logrus.FieldLogger, timeout time.Duration, r requests.Request[A]) (A, error) {
+	if timeout <= 0 {
+		return r(l, ctx)
+	}
+	tctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		a   A
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		a, err := r(l, tctx)
+		done <- result{a: a, err: err}
+	}()
+	select {
+	case res := <-done:
+		return res.a, res.err
+	case <-tctx.Done():
+		var a A
+		if ctx.Err() != nil {
+			return a, ctx.Err()
+		}
+		return a, ErrTimeout
+	}
+}
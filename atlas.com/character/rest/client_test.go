@@ -0,0 +1,273 @@
+package rest_test
+
+import (
+	"atlas-character/rest"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// statusError is a response with an unsuccessful status.
+type statusError int
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("responded with status [%d]", int(e))
+}
+
+func (e statusError) StatusCode() int {
+	return int(e)
+}
+
+var errUnavailable = statusError(http.StatusServiceUnavailable)
+
+func testLogger() logrus.FieldLogger {
+	l, _ := test.NewNullLogger()
+	return l
+}
+
+// failingRequest fails the first failures times it is made, counting every attempt.
+func failingRequest(attempts *int, failures int) func(l logrus.FieldLogger, ctx context.Context) (string, error) {
+	return func(l logrus.FieldLogger, ctx context.Context) (string, error) {
+		*attempts += 1
+		if *attempts <= failures {
+			return "", errUnavailable
+		}
+		return "ok", nil
+	}
+}
+
+func TestResilientRetriesIdempotentRequests(t *testing.T) {
+	t.Setenv("REST_CLIENT_BASE_DELAY", "1ms")
+	t.Setenv("REST_CLIENT_ATTEMPTS", "3")
+
+	attempts := 0
+	res, err := rest.Resilient("http://retry-get/api/", true, failingRequest(&attempts, 2))(testLogger(), context.Background())
+	if err != nil || res != "ok" || attempts != 3 {
+		t.Fatalf("Expected success on the third attempt, got: %s %v after %d attempts", res, err, attempts)
+	}
+
+	attempts = 0
+	_, err = rest.Resilient("http://retry-post/api/", false, failingRequest(&attempts, 2))(testLogger(), context.Background())
+	if !errors.Is(err, errUnavailable) || attempts != 1 {
+		t.Fatalf("Expected non-idempotent request to be attempted once, got: %v after %d attempts", err, attempts)
+	}
+}
+
+func TestResilientTimeout(t *testing.T) {
+	t.Setenv("REST_CLIENT_TIMEOUT", "20ms")
+	t.Setenv("REST_CLIENT_ATTEMPTS", "1")
+
+	start := time.Now()
+	_, err := rest.Resilient("http://timeout/api/", true, func(l logrus.FieldLogger, ctx context.Context) (string, error) {
+		time.Sleep(time.Second)
+		return "late", nil
+	})(testLogger(), context.Background())
+	if !errors.Is(err, rest.ErrTimeout) || time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("Expected request to time out promptly, got: %v after %s", err, time.Since(start))
+	}
+}
+
+func TestResilientBreaker(t *testing.T) {
+	t.Setenv("REST_CLIENT_ATTEMPTS", "1")
+	t.Setenv("REST_CLIENT_FAILURE_THRESHOLD", "2")
+	t.Setenv("REST_CLIENT_COOLDOWN", "50ms")
+
+	attempts := 0
+	r := rest.Resilient("http://breaker/api/", true, failingRequest(&attempts, 2))
+	for i := 0; i < 2; i++ {
+		if _, err := r(testLogger(), context.Background()); !errors.Is(err, errUnavailable) {
+			t.Fatalf("Expected failure, got: %v", err)
+		}
+	}
+	if _, err := r(testLogger(), context.Background()); !errors.Is(err, rest.ErrCircuitOpen) || attempts != 2 {
+		t.Fatalf("Expected open circuit to fail without a request, got: %v after %d attempts", err, attempts)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if res, err := r(testLogger(), context.Background()); err != nil || res != "ok" {
+		t.Fatalf("Expected trial request after cooldown to succeed, got: %s %v", res, err)
+	}
+
+	for _, m := range rest.ClientMetrics() {
+		if m.Name != "http://breaker" {
+			continue
+		}
+		if m.Breaker != "closed" || m.Requests != 3 || m.Failures != 2 || m.Rejections != 1 {
+			t.Fatalf("Unexpected metrics: %+v", m)
+		}
+		return
+	}
+	t.Fatalf("No metrics for dependency.")
+}
+
+func TestResilientClientError(t *testing.T) {
+	t.Setenv("REST_CLIENT_BASE_DELAY", "1ms")
+	t.Setenv("REST_CLIENT_ATTEMPTS", "3")
+	t.Setenv("REST_CLIENT_FAILURE_THRESHOLD", "1")
+
+	attempts := 0
+	r := rest.Resilient("http://client-error/api/", true, func(l logrus.FieldLogger, ctx context.Context) (string, error) {
+		attempts += 1
+		return "", statusError(http.StatusNotFound)
+	})
+	for i := 1; i <= 2; i++ {
+		_, err := r(testLogger(), context.Background())
+		if !errors.Is(err, statusError(http.StatusNotFound)) || errors.Is(err, rest.ErrCircuitOpen) || attempts != i {
+			t.Fatalf("Expected client error to be returned without retrying, got: %v after %d attempts", err, attempts)
+		}
+	}
+
+	for _, m := range rest.ClientMetrics() {
+		if m.Name != "http://client-error" {
+			continue
+		}
+		if m.Breaker != "closed" || m.Requests != 2 || m.Failures != 0 || m.Retries != 0 || m.Rejections != 0 {
+			t.Fatalf("Unexpected metrics: %+v", m)
+		}
+		return
+	}
+	t.Fatalf("No metrics for dependency.")
+}
+
+func TestIsNotFound(t *testing.T) {
+	if !rest.IsNotFound(fmt.Errorf("retrieving skill: %w", statusError(http.StatusNotFound))) {
+		t.Fatalf("Expected a 404 response to be not found.")
+	}
+	for _, err := range []error{nil, errUnavailable, statusError(http.StatusBadRequest), errors.New("connection refused")} {
+		if rest.IsNotFound(err) {
+			t.Fatalf("Expected [%v] not to be not found.", err)
+		}
+	}
+}
+
+// dependencyMetrics finds the metrics of the dependency named, failing t should there be none.
+func dependencyMetrics(t *testing.T, name string) rest.DependencyMetrics {
+	for _, m := range rest.ClientMetrics() {
+		if m.Name == name {
+			return m
+		}
+	}
+	t.Fatalf("No metrics for dependency [%s].", name)
+	return rest.DependencyMetrics{}
+}
+
+func TestResilientCallerCancellation(t *testing.T) {
+	t.Setenv("REST_CLIENT_ATTEMPTS", "1")
+	t.Setenv("REST_CLIENT_FAILURE_THRESHOLD", "1")
+	t.Setenv("REST_CLIENT_COOLDOWN", "20ms")
+
+	attempts := 0
+	var cancelled atomic.Bool
+	r := rest.Resilient("http://cancelled/api/", true, func(l logrus.FieldLogger, ctx context.Context) (string, error) {
+		if cancelled.Load() {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		return failingRequest(&attempts, 1)(l, ctx)
+	})
+	if _, err := r(testLogger(), context.Background()); !errors.Is(err, errUnavailable) {
+		t.Fatalf("Expected failure, got: %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	// The caller gives up on the trial request, which must not close the breaker.
+	cancelled.Store(true)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := r(testLogger(), ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected cancellation, got: %v", err)
+	}
+	if m := dependencyMetrics(t, "http://cancelled"); m.Breaker != "open" || m.Failures != 1 {
+		t.Fatalf("Unexpected metrics: %+v", m)
+	}
+
+	cancelled.Store(false)
+	if res, err := r(testLogger(), context.Background()); err != nil || res != "ok" {
+		t.Fatalf("Expected the next request to be let through as the trial, got: %s %v", res, err)
+	}
+	if m := dependencyMetrics(t, "http://cancelled"); m.Breaker != "closed" {
+		t.Fatalf("Unexpected metrics: %+v", m)
+	}
+}
+
+func TestResilientCallerDeadline(t *testing.T) {
+	t.Setenv("REST_CLIENT_BASE_DELAY", "1ms")
+	t.Setenv("REST_CLIENT_ATTEMPTS", "3")
+	t.Setenv("REST_CLIENT_FAILURE_THRESHOLD", "1")
+
+	var attempts atomic.Int32
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := rest.Resilient("http://deadline/api/", true, func(l logrus.FieldLogger, ctx context.Context) (string, error) {
+		attempts.Add(1)
+		<-ctx.Done()
+		return "", ctx.Err()
+	})(testLogger(), ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || attempts.Load() != 1 {
+		t.Fatalf("Expected the caller's deadline to end the request, got: %v after %d attempts", err, attempts.Load())
+	}
+	if m := dependencyMetrics(t, "http://deadline"); m.Breaker != "closed" || m.Failures != 0 {
+		t.Fatalf("Unexpected metrics: %+v", m)
+	}
+}
+
+func TestResilientUrlError(t *testing.T) {
+	t.Setenv("REST_CLIENT_BASE_DELAY", "1ms")
+	t.Setenv("REST_CLIENT_ATTEMPTS", "3")
+	t.Setenv("REST_CLIENT_FAILURE_THRESHOLD", "0")
+
+	for _, tc := range []struct {
+		name     string
+		cause    error
+		attempts int
+	}{
+		{"unsupported scheme", errors.New("unsupported protocol scheme \"\""), 1},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, 3},
+		{"connection closed", io.EOF, 3},
+	} {
+		attempts := 0
+		_, err := rest.Resilient("http://url-error/api/", true, func(l logrus.FieldLogger, ctx context.Context) (string, error) {
+			attempts += 1
+			return "", &url.Error{Op: "Get", URL: "http://url-error/api/", Err: tc.cause}
+		})(testLogger(), context.Background())
+		if err == nil || attempts != tc.attempts {
+			t.Fatalf("%s: expected %d attempts, got %d: %v", tc.name, tc.attempts, attempts, err)
+		}
+	}
+}
+
+func TestResilientDelete(t *testing.T) {
+	t.Setenv("REST_CLIENT_BASE_DELAY", "1ms")
+	t.Setenv("REST_CLIENT_ATTEMPTS", "3")
+
+	// The first attempt deletes the resource, but its response is lost.
+	attempts := 0
+	err := rest.ResilientDelete("http://delete/api/", func(l logrus.FieldLogger, ctx context.Context) error {
+		attempts += 1
+		if attempts == 1 {
+			return errUnavailable
+		}
+		return statusError(http.StatusNotFound)
+	})(testLogger(), context.Background())
+	if err != nil || attempts != 2 {
+		t.Fatalf("Expected the retried delete to succeed, got: %v after %d attempts", err, attempts)
+	}
+
+	attempts = 0
+	err = rest.ResilientDelete("http://delete/api/", func(l logrus.FieldLogger, ctx context.Context) error {
+		attempts += 1
+		return statusError(http.StatusNotFound)
+	})(testLogger(), context.Background())
+	if !rest.IsNotFound(err) || attempts != 1 {
+		t.Fatalf("Expected a delete of nothing to fail, got: %v after %d attempts", err, attempts)
+	}
+}
@@ -22,8 +22,9 @@ func (h HandlerDependency) Logger() logrus.FieldLogger {
 	return h.l
 }
 
+// DB provides the database bound to the request context, so statements are scoped to the request's tenant.
 func (h HandlerDependency) DB() *gorm.DB {
-	return h.db
+	return h.db.WithContext(h.ctx)
 }
 
 func (h HandlerDependency) Context() context.Context {
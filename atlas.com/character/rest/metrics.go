@@ -0,0 +1,84 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"sort"
+	"sync/atomic"
+)
+
+type metrics struct {
+	requests   atomic.Uint64
+	failures   atomic.Uint64
+	timeouts   atomic.Uint64
+	retries    atomic.Uint64
+	rejections atomic.Uint64
+}
+
+func (m *metrics) requested() {
+	m.requests.Add(1)
+}
+
+func (m *metrics) failed(timeout bool) {
+	m.failures.Add(1)
+	if timeout {
+		m.timeouts.Add(1)
+	}
+}
+
+func (m *metrics) retried() {
+	m.retries.Add(1)
+}
+
+func (m *metrics) rejected() {
+	m.rejections.Add(1)
+}
+
+// DependencyMetrics counts the calls made to a dependency since start-up. Rejections are calls failed by an open
+// breaker without being made.
+type DependencyMetrics struct {
+	Name       string `json:"name"`
+	Breaker    string `json:"breaker"`
+	Requests   uint64 `json:"requests"`
+	Failures   uint64 `json:"failures"`
+	Timeouts   uint64 `json:"timeouts"`
+	Retries    uint64 `json:"retries"`
+	Rejections uint64 `json:"rejections"`
+}
+
+// ClientMetrics yields the metrics of every dependency called so far.
+func ClientMetrics() []DependencyMetrics {
+	r := getClientRegistry()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	results := make([]DependencyMetrics, 0)
+	for _, d := range r.dependencies {
+		results = append(results, DependencyMetrics{
+			Name:       d.name,
+			Breaker:    d.breaker.State(),
+			Requests:   d.metrics.requests.Load(),
+			Failures:   d.metrics.failures.Load(),
+			Timeouts:   d.metrics.timeouts.Load(),
+			Retries:    d.metrics.retries.Load(),
+			Rejections: d.metrics.rejections.Load(),
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// InitClientMetricsResource registers the endpoint reporting the metrics of outbound calls.
+func InitClientMetricsResource(router *mux.Router, l logrus.FieldLogger) {
+	router.HandleFunc("/clients/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		err := json.NewEncoder(w).Encode(ClientMetrics())
+		if err != nil {
+			l.WithError(err).Errorf("Writing client metrics.")
+		}
+	}).Methods(http.MethodGet)
+}
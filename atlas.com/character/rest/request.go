@@ -10,7 +10,7 @@ func MakeGetRequest[A any](url string) requests.Request[A] {
 	return func(l logrus.FieldLogger, ctx context.Context) (A, error) {
 		sd := requests.AddHeaderDecorator(requests.SpanHeaderDecorator(ctx))
 		td := requests.AddHeaderDecorator(requests.TenantHeaderDecorator(ctx))
-		return requests.MakeGetRequest[A](url, sd, td)(l, ctx)
+		return Resilient(url, true, requests.MakeGetRequest[A](url, sd, td))(l, ctx)
 	}
 }
 
@@ -18,7 +18,7 @@ func MakePostRequest[A any](url string, i interface{}) requests.Request[A] {
 	return func(l logrus.FieldLogger, ctx context.Context) (A, error) {
 		sd := requests.AddHeaderDecorator(requests.SpanHeaderDecorator(ctx))
 		td := requests.AddHeaderDecorator(requests.TenantHeaderDecorator(ctx))
-		return requests.MakePostRequest[A](url, i, sd, td)(l, ctx)
+		return Resilient(url, false, requests.MakePostRequest[A](url, i, sd, td))(l, ctx)
 	}
 }
 
@@ -26,7 +26,7 @@ func MakePatchRequest[A any](url string, i interface{}) requests.Request[A] {
 	return func(l logrus.FieldLogger, ctx context.Context) (A, error) {
 		sd := requests.AddHeaderDecorator(requests.SpanHeaderDecorator(ctx))
 		td := requests.AddHeaderDecorator(requests.TenantHeaderDecorator(ctx))
-		return requests.MakePatchRequest[A](url, i, sd, td)(l, ctx)
+		return Resilient(url, false, requests.MakePatchRequest[A](url, i, sd, td))(l, ctx)
 	}
 }
 
@@ -34,6 +34,6 @@ func MakeDeleteRequest(url string) requests.EmptyBodyRequest {
 	return func(l logrus.FieldLogger, ctx context.Context) error {
 		sd := requests.AddHeaderDecorator(requests.SpanHeaderDecorator(ctx))
 		td := requests.AddHeaderDecorator(requests.TenantHeaderDecorator(ctx))
-		return requests.MakeDeleteRequest(url, sd, td)(l, ctx)
+		return ResilientDelete(url, requests.MakeDeleteRequest(url, sd, td))(l, ctx)
 	}
 }
@@ -1,23 +1,94 @@
 package retry
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"math/rand"
 	"time"
 )
 
+var ErrExhausted = errors.New("max retry reached")
+
+// Policy decides, after the failed attempt numbered attempt, whether another attempt is made and how long to wait
+// before it. elapsed is the time since the first attempt started.
+type Policy func(attempt int, elapsed time.Duration) (delay time.Duration, ok bool)
+
+// Constant makes up to attempts attempts, waiting delay between them.
+func Constant(delay time.Duration, attempts int) Policy {
+	return func(attempt int, elapsed time.Duration) (time.Duration, bool) {
+		return delay, attempt < attempts
+	}
+}
+
+// Exponential makes up to attempts attempts, waiting base before the first retry and doubling the wait with each
+// further retry, up to maximum.
+func Exponential(base time.Duration, maximum time.Duration, attempts int) Policy {
+	return func(attempt int, elapsed time.Duration) (time.Duration, bool) {
+		if attempt >= attempts {
+			return 0, false
+		}
+		delay := base
+		for i := 1; i < attempt && delay < maximum; i++ {
+			delay *= 2
+		}
+		return min(delay, maximum), true
+	}
+}
+
+// Jittered waits a duration drawn uniformly up to the delay of p, so that callers failing together do not retry
+// together.
+func Jittered(p Policy) Policy {
+	return func(attempt int, elapsed time.Duration) (time.Duration, bool) {
+		delay, ok := p(attempt, elapsed)
+		if !ok || delay <= 0 {
+			return delay, ok
+		}
+		return time.Duration(rand.Int63n(int64(delay) + 1)), true
+	}
+}
+
+// MaxElapsed stops the retries of p once the next attempt would start more than maximum after the first. A maximum of
+// 0 leaves p unbounded.
+func MaxElapsed(p Policy, maximum time.Duration) Policy {
+	return func(attempt int, elapsed time.Duration) (time.Duration, bool) {
+		delay, ok := p(attempt, elapsed)
+		if !ok || maximum <= 0 {
+			return delay, ok
+		}
+		return delay, elapsed+delay <= maximum
+	}
+}
+
+// TryFunc makes the numbered attempt, reporting whether a failure may be retried.
 type TryFunc func(attempt int) (retry bool, err error)
 
-func Try(fn TryFunc, retries int) error {
-	attempt := 1
-	for {
+// Try attempts fn until it succeeds, fails without asking to be retried, or policy stops retrying. Waits end early
+// when ctx is done. The error of the last attempt is wrapped in the error returned.
+func Try(ctx context.Context, policy Policy, fn TryFunc) error {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
 		cont, err := fn(attempt)
-		if !cont || err == nil {
+		if err == nil {
 			return nil
 		}
-		attempt++
-		if attempt > retries {
-			return errors.New("max retry reached")
+		if !cont {
+			return err
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		}
+		delay, ok := policy(attempt, time.Since(start))
+		if !ok {
+			return fmt.Errorf("%w after %d attempts: %w", ErrExhausted, attempt, err)
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-t.C:
 		}
-		time.Sleep(1 * time.Second)
 	}
-}
\ No newline at end of file
+}
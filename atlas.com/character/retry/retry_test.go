@@ -0,0 +1,81 @@
+package retry_test
+
+import (
+	"atlas-character/retry"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errFailed = errors.New("failed")
+
+func TestTry(t *testing.T) {
+	attempts := 0
+	err := retry.Try(context.Background(), retry.Constant(0, 3), func(attempt int) (bool, error) {
+		attempts = attempt
+		if attempt < 2 {
+			return true, errFailed
+		}
+		return false, nil
+	})
+	if err != nil || attempts != 2 {
+		t.Fatalf("Expected success on the second attempt, got: %v after %d attempts", err, attempts)
+	}
+
+	err = retry.Try(context.Background(), retry.Constant(0, 3), func(attempt int) (bool, error) {
+		attempts = attempt
+		return false, errFailed
+	})
+	if err != errFailed || attempts != 1 {
+		t.Fatalf("Expected failure which is not retried to be returned, got: %v after %d attempts", err, attempts)
+	}
+
+	err = retry.Try(context.Background(), retry.Constant(0, 3), func(attempt int) (bool, error) {
+		attempts = attempt
+		return true, errFailed
+	})
+	if !errors.Is(err, retry.ErrExhausted) || !errors.Is(err, errFailed) || attempts != 3 {
+		t.Fatalf("Expected last error wrapped once retries are exhausted, got: %v after %d attempts", err, attempts)
+	}
+}
+
+func TestTryCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := retry.Try(ctx, retry.Constant(time.Second, 10), func(attempt int) (bool, error) {
+		return true, errFailed
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errFailed) || time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("Expected wait to end with the context, got: %v after %s", err, time.Since(start))
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	p := retry.Exponential(100*time.Millisecond, time.Second, 6)
+	for attempt, expected := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second} {
+		if delay, ok := p(attempt+1, 0); !ok || delay != expected {
+			t.Fatalf("Expected delay [%s] after attempt [%d], got: [%s] %t", expected, attempt+1, delay, ok)
+		}
+	}
+	if _, ok := p(6, 0); ok {
+		t.Fatalf("Expected no retry after the last attempt.")
+	}
+
+	j := retry.Jittered(p)
+	for i := 0; i < 100; i++ {
+		if delay, ok := j(3, 0); !ok || delay < 0 || delay > 400*time.Millisecond {
+			t.Fatalf("Expected jittered delay up to [400ms], got: [%s] %t", delay, ok)
+		}
+	}
+
+	m := retry.MaxElapsed(retry.Constant(time.Second, 10), 5*time.Second)
+	if _, ok := m(1, 4*time.Second); !ok {
+		t.Fatalf("Expected retry within the elapsed bound.")
+	}
+	if _, ok := m(2, 4500*time.Millisecond); ok {
+		t.Fatalf("Expected no retry beyond the elapsed bound.")
+	}
+}
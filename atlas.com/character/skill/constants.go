@@ -0,0 +1,7 @@
+package skill
+
+const (
+	AssassinClawMastery    uint32 = 4100000
+	NightWalkerClawMastery uint32 = 14100000
+	GunslingerGunMastery   uint32 = 5200000
+)
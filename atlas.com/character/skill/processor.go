@@ -0,0 +1,20 @@
+package skill
+
+import (
+	"context"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/requests"
+	"github.com/sirupsen/logrus"
+)
+
+func byIdModelProvider(l logrus.FieldLogger, ctx context.Context) func(characterId uint32, id uint32) model.Provider[Model] {
+	return func(characterId uint32, id uint32) model.Provider[Model] {
+		return requests.Provider[RestModel, Model](l, ctx)(requestById(characterId, id), Extract)
+	}
+}
+
+func GetById(l logrus.FieldLogger, ctx context.Context) func(characterId uint32, id uint32) (Model, error) {
+	return func(characterId uint32, id uint32) (Model, error) {
+		return byIdModelProvider(l, ctx)(characterId, id)()
+	}
+}
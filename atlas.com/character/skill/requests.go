@@ -0,0 +1,21 @@
+package skill
+
+import (
+	"atlas-character/rest"
+	"fmt"
+	"github.com/Chronicle20/atlas-rest/requests"
+	"os"
+)
+
+const (
+	skillsForCharacter = "characters/%d/skills"
+	skillById          = skillsForCharacter + "/%d"
+)
+
+func getBaseRequest() string {
+	return os.Getenv("SKILL_SERVICE_URL")
+}
+
+func requestById(characterId uint32, id uint32) requests.Request[RestModel] {
+	return rest.MakeGetRequest[RestModel](fmt.Sprintf(getBaseRequest()+skillById, characterId, id))
+}
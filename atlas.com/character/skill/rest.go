@@ -0,0 +1,32 @@
+package skill
+
+import "strconv"
+
+type RestModel struct {
+	Id    uint32 `json:"-"`
+	Level byte   `json:"level"`
+}
+
+func (r RestModel) GetName() string {
+	return "skills"
+}
+
+func (r RestModel) GetID() string {
+	return strconv.Itoa(int(r.Id))
+}
+
+func (r *RestModel) SetID(strId string) error {
+	id, err := strconv.Atoi(strId)
+	if err != nil {
+		return err
+	}
+	r.Id = uint32(id)
+	return nil
+}
+
+func Extract(m RestModel) (Model, error) {
+	return Model{
+		id:    m.Id,
+		level: m.Level,
+	}, nil
+}
@@ -2,10 +2,30 @@ package slottable
 
 import (
 	"atlas-character/asset"
+	"context"
+	"errors"
+	"fmt"
 	"github.com/Chronicle20/atlas-model/model"
+	"gorm.io/gorm"
 	"sort"
 )
 
+var ErrInventoryFull = errors.New("inventory full")
+
+// InventoryFullError is returned when an inventory has no free slot within its capacity. It matches ErrInventoryFull.
+type InventoryFullError struct {
+	InventoryId uint32
+	Capacity    uint32
+}
+
+func (e InventoryFullError) Error() string {
+	return fmt.Sprintf("inventory [%d] full at capacity [%d]", e.InventoryId, e.Capacity)
+}
+
+func (e InventoryFullError) Is(target error) bool {
+	return target == ErrInventoryFull
+}
+
 func MinFreeSlot(items []asset.Slottable) int16 {
 	slot := int16(1)
 	i := 0
@@ -24,17 +44,23 @@ func MinFreeSlot(items []asset.Slottable) int16 {
 	}
 }
 
-func GetNextFreeSlot(provider model.Provider[[]asset.Slottable]) (int16, error) {
+// GetNextFreeSlot returns the lowest free slot of the inventory, or an InventoryFullError when that slot lies beyond capacity. A capacity of 0 is treated as unbounded.
+func GetNextFreeSlot(provider model.Provider[[]asset.Slottable], inventoryId uint32, capacity uint32) (int16, error) {
 	es, err := provider()
 	if err != nil {
 		return 1, err
 	}
-	if len(es) == 0 {
-		return 1, nil
-	}
 
 	sort.Slice(es, func(i, j int) bool {
 		return es[i].Slot() < es[j].Slot()
 	})
-	return MinFreeSlot(es), nil
+	slot := MinFreeSlot(es)
+	if capacity > 0 && uint32(slot) > capacity {
+		return 0, InventoryFullError{InventoryId: inventoryId, Capacity: capacity}
+	}
+	return slot, nil
 }
+
+// CapacityProvider supplies the capacity of an inventory. Inventories are owned by the inventory package, which
+// supplies the implementation.
+type CapacityProvider func(db *gorm.DB) func(ctx context.Context) func(inventoryId uint32) model.Provider[uint32]
@@ -3,6 +3,8 @@ package slottable_test
 import (
 	"atlas-character/asset"
 	"atlas-character/slottable"
+	"errors"
+	"github.com/Chronicle20/atlas-model/model"
 	"testing"
 )
 
@@ -76,3 +78,24 @@ func TestMinFreeSlot5(t *testing.T) {
 		t.Fatalf("MinFreeSlot expected=%d, got=%d", 4, result)
 	}
 }
+
+// TestGetNextFreeSlotFull tests GetNextFreeSlot when every slot up to capacity is taken.
+func TestGetNextFreeSlotFull(t *testing.T) {
+	items := []asset.Slottable{
+		TestModel{slot: 2},
+		TestModel{slot: 1},
+		TestModel{slot: 3},
+	}
+	_, err := slottable.GetNextFreeSlot(model.FixedProvider(items), 7, 3)
+	if !errors.Is(err, slottable.ErrInventoryFull) {
+		t.Fatalf("GetNextFreeSlot expected=%v, got=%v", slottable.ErrInventoryFull, err)
+	}
+	var fe slottable.InventoryFullError
+	if !errors.As(err, &fe) || fe.InventoryId != 7 || fe.Capacity != 3 {
+		t.Fatalf("GetNextFreeSlot expected inventory [7] full at capacity [3], got=%v", err)
+	}
+	result, err := slottable.GetNextFreeSlot(model.FixedProvider(items), 1, 4)
+	if err != nil || result != 4 {
+		t.Fatalf("GetNextFreeSlot expected=%d, got=%d (%v)", 4, result, err)
+	}
+}